@@ -0,0 +1,252 @@
+package analytics
+
+// Lap time analytics such as fastest laps, sector bests, theoretical best laps and lap deltas.
+
+import (
+	"sort"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// SectorBests represents the best sector times in seconds, zero when no valid time is known
+type SectorBests struct {
+	Sector1 float64 // Best sector 1 time in seconds
+	Sector2 float64 // Best sector 2 time in seconds
+	Sector3 float64 // Best sector 3 time in seconds
+}
+
+// Ideal returns the theoretical best lap built from the best sectors.
+// Returns zero if any of the sectors is missing.
+func (b SectorBests) Ideal() float64 {
+	if b.Sector1 <= 0 || b.Sector2 <= 0 || b.Sector3 <= 0 {
+		return 0
+	}
+	return b.Sector1 + b.Sector2 + b.Sector3
+}
+
+// update lowers the bests with the sectors of a lap and reports which sectors improved.
+// A sector only equalling the best does not improve it.
+func (b *SectorBests) update(lap openf1go.Lap) [3]bool {
+	var improved [3]bool
+	for i, pair := range []struct {
+		best     *float64
+		duration float64
+	}{
+		{&b.Sector1, lap.DurationSector1},
+		{&b.Sector2, lap.DurationSector2},
+		{&b.Sector3, lap.DurationSector3},
+	} {
+		if pair.duration > 0 && (*pair.best == 0 || pair.duration < *pair.best) {
+			*pair.best = pair.duration
+			improved[i] = true
+		}
+	}
+	return improved
+}
+
+// SectorClass classifies a sector time the way timing screens color it
+type SectorClass int
+
+const (
+	SectorNone   SectorClass = iota // No valid time for the sector
+	SectorYellow                    // Valid time that is not a personal best
+	SectorGreen                     // Personal best at the time it was set
+	SectorPurple                    // Overall best at the time it was set
+)
+
+// String returns the color name of the sector class
+func (s SectorClass) String() string {
+	switch s {
+	case SectorYellow:
+		return "yellow"
+	case SectorGreen:
+		return "green"
+	case SectorPurple:
+		return "purple"
+	default:
+		return "none"
+	}
+}
+
+// LapSectors represents a lap with the classification of each of its sectors
+type LapSectors struct {
+	Lap     openf1go.Lap   // The classified lap
+	Sectors [3]SectorClass // Classification of sectors 1 to 3
+}
+
+// LapDelta represents the difference between a lap and reference laps in seconds
+type LapDelta struct {
+	DriverNumber   int     // Driver's unique number
+	LapNumber      int     // Lap number in the session
+	LapDuration    float64 // Duration of the lap in seconds
+	ToPersonalBest float64 // Difference to the driver's fastest lap
+	ToFastest      float64 // Difference to the overall fastest lap
+}
+
+// pitInLaps returns the set of laps ending in the pit lane keyed by driver number and lap number.
+// A lap is a pit in lap when the driver's following lap is a pit out lap.
+func pitInLaps(laps openf1go.LapsResponse) map[[2]int]bool {
+	in := map[[2]int]bool{}
+	for _, lap := range laps {
+		if lap.IsPitOutLap && lap.LapNumber > 1 {
+			in[[2]int{lap.DriverNumber, lap.LapNumber - 1}] = true
+		}
+	}
+	return in
+}
+
+// CleanLaps returns the laps that can be used for pace analysis.
+// Pit out laps, pit in laps and laps without a duration are excluded.
+func CleanLaps(laps openf1go.LapsResponse) openf1go.LapsResponse {
+	in := pitInLaps(laps)
+	clean := openf1go.LapsResponse{}
+
+	for _, lap := range laps {
+		if lap.IsPitOutLap || lap.LapDuration <= 0 || in[[2]int{lap.DriverNumber, lap.LapNumber}] {
+			continue
+		}
+		clean = append(clean, lap)
+	}
+
+	return clean
+}
+
+// FastestLap returns the fastest clean lap of all drivers
+func FastestLap(laps openf1go.LapsResponse) (openf1go.Lap, bool) {
+	var fastest openf1go.Lap
+
+	for _, lap := range CleanLaps(laps) {
+		if fastest.LapDuration == 0 || lap.LapDuration < fastest.LapDuration {
+			fastest = lap
+		}
+	}
+
+	return fastest, fastest.LapDuration > 0
+}
+
+// FastestLaps returns the fastest clean lap of each driver keyed by driver number
+func FastestLaps(laps openf1go.LapsResponse) map[int]openf1go.Lap {
+	fastest := map[int]openf1go.Lap{}
+
+	for _, lap := range CleanLaps(laps) {
+		if best, ok := fastest[lap.DriverNumber]; !ok || lap.LapDuration < best.LapDuration {
+			fastest[lap.DriverNumber] = lap
+		}
+	}
+
+	return fastest
+}
+
+// PersonalSectorBests returns the best sector times of each driver keyed by driver number
+func PersonalSectorBests(laps openf1go.LapsResponse) map[int]SectorBests {
+	bests := map[int]SectorBests{}
+
+	for _, lap := range CleanLaps(laps) {
+		b := bests[lap.DriverNumber]
+		b.update(lap)
+		bests[lap.DriverNumber] = b
+	}
+
+	return bests
+}
+
+// OverallSectorBests returns the best sector times across all drivers
+func OverallSectorBests(laps openf1go.LapsResponse) SectorBests {
+	var bests SectorBests
+
+	for _, lap := range CleanLaps(laps) {
+		bests.update(lap)
+	}
+
+	return bests
+}
+
+// TheoreticalBests returns the ideal lap of each driver keyed by driver number.
+// Drivers missing a valid time in any sector are left out.
+func TheoreticalBests(laps openf1go.LapsResponse) map[int]float64 {
+	ideal := map[int]float64{}
+
+	for driverNumber, bests := range PersonalSectorBests(laps) {
+		if t := bests.Ideal(); t > 0 {
+			ideal[driverNumber] = t
+		}
+	}
+
+	return ideal
+}
+
+// ClassifySectors classifies each sector of each clean lap as purple, green or yellow.
+// Laps are replayed in the order they were driven so each sector is judged against the bests at that moment.
+func ClassifySectors(laps openf1go.LapsResponse) []LapSectors {
+	clean := CleanLaps(laps)
+	sortByStart(clean)
+
+	var overall SectorBests
+	personal := map[int]*SectorBests{}
+	classified := make([]LapSectors, 0, len(clean))
+
+	for _, lap := range clean {
+		if personal[lap.DriverNumber] == nil {
+			personal[lap.DriverNumber] = &SectorBests{}
+		}
+
+		pb := personal[lap.DriverNumber].update(lap)
+		ob := overall.update(lap)
+
+		entry := LapSectors{Lap: lap}
+		for i, duration := range []float64{lap.DurationSector1, lap.DurationSector2, lap.DurationSector3} {
+			switch {
+			case duration <= 0:
+				entry.Sectors[i] = SectorNone
+			case ob[i]:
+				entry.Sectors[i] = SectorPurple
+			case pb[i]:
+				entry.Sectors[i] = SectorGreen
+			default:
+				entry.Sectors[i] = SectorYellow
+			}
+		}
+		classified = append(classified, entry)
+	}
+
+	return classified
+}
+
+// LapDeltas returns the delta of every clean lap to the driver's fastest lap and to the overall fastest lap
+func LapDeltas(laps openf1go.LapsResponse) []LapDelta {
+	clean := CleanLaps(laps)
+	personal := FastestLaps(clean)
+	fastest, _ := FastestLap(clean)
+	sortByStart(clean)
+
+	deltas := make([]LapDelta, 0, len(clean))
+	for _, lap := range clean {
+		deltas = append(deltas, LapDelta{
+			DriverNumber:   lap.DriverNumber,
+			LapNumber:      lap.LapNumber,
+			LapDuration:    lap.LapDuration,
+			ToPersonalBest: lap.LapDuration - personal[lap.DriverNumber].LapDuration,
+			ToFastest:      lap.LapDuration - fastest.LapDuration,
+		})
+	}
+
+	return deltas
+}
+
+// sortByStart orders laps by start time, falling back to lap and driver number.
+// Laps without a start time, usually the first lap, come before every lap with one.
+func sortByStart(laps openf1go.LapsResponse) {
+	sort.SliceStable(laps, func(i, j int) bool {
+		a, b := laps[i], laps[j]
+		if a.DateStart.IsZero() != b.DateStart.IsZero() {
+			return a.DateStart.IsZero()
+		}
+		if !a.DateStart.Equal(b.DateStart) {
+			return a.DateStart.Before(b.DateStart)
+		}
+		if a.LapNumber != b.LapNumber {
+			return a.LapNumber < b.LapNumber
+		}
+		return a.DriverNumber < b.DriverNumber
+	})
+}
@@ -0,0 +1,251 @@
+package analytics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+var lapStart = time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// lap returns a lap of a driver starting the given number of seconds into the session with its sector times
+func lap(driverNumber, lapNumber, seconds int, sectors ...float64) openf1go.Lap {
+	l := openf1go.Lap{DriverNumber: driverNumber, LapNumber: lapNumber, DateStart: lapStart.Add(time.Duration(seconds) * time.Second)}
+	if len(sectors) == 3 {
+		l.DurationSector1, l.DurationSector2, l.DurationSector3 = sectors[0], sectors[1], sectors[2]
+		l.LapDuration = sectors[0] + sectors[1] + sectors[2]
+	}
+	return l
+}
+
+// lapNumbers returns the driver and lap number of each lap
+func lapNumbers(laps openf1go.LapsResponse) [][2]int {
+	numbers := [][2]int{}
+	for _, l := range laps {
+		numbers = append(numbers, [2]int{l.DriverNumber, l.LapNumber})
+	}
+	return numbers
+}
+
+func TestCleanLaps(t *testing.T) {
+	pitOut := lap(1, 4, 300, 31, 41, 31)
+	pitOut.IsPitOutLap = true
+
+	tests := []struct {
+		name string
+		laps openf1go.LapsResponse
+		want [][2]int
+	}{
+		{name: "timed laps", laps: openf1go.LapsResponse{lap(1, 2, 100, 30, 40, 30), lap(44, 2, 101, 30, 40, 30)}, want: [][2]int{{1, 2}, {44, 2}}},
+		{name: "without a duration", laps: openf1go.LapsResponse{lap(1, 1, 0), lap(1, 2, 100, 30, 40, 30)}, want: [][2]int{{1, 2}}},
+		{
+			name: "pit in and pit out laps",
+			laps: openf1go.LapsResponse{lap(1, 2, 100, 30, 40, 30), lap(1, 3, 200, 30, 40, 50), pitOut, lap(1, 5, 400, 30, 40, 30)},
+			want: [][2]int{{1, 2}, {1, 5}},
+		},
+		{
+			name: "pit out of another driver",
+			laps: openf1go.LapsResponse{lap(44, 3, 200, 30, 40, 30), pitOut},
+			want: [][2]int{{44, 3}},
+		},
+		{name: "no laps", want: [][2]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lapNumbers(CleanLaps(tt.laps)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CleanLaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFastestLaps(t *testing.T) {
+	pitOut := lap(44, 3, 200, 20, 20, 20) // Fastest on paper, but a pit out lap
+	pitOut.IsPitOutLap = true
+	laps := openf1go.LapsResponse{
+		lap(1, 1, 0),
+		lap(1, 2, 100, 30, 40, 30.5),
+		lap(1, 3, 200, 30, 40, 30),
+		lap(44, 2, 101, 30, 40, 31),
+		pitOut,
+		lap(44, 4, 300, 30, 40, 30.2),
+	}
+
+	fastest, ok := FastestLap(laps)
+	if !ok || fastest.DriverNumber != 1 || fastest.LapNumber != 3 {
+		t.Errorf("FastestLap() = lap %d of driver %d, %v, want lap 3 of driver 1", fastest.LapNumber, fastest.DriverNumber, ok)
+	}
+
+	tests := []struct {
+		driverNumber int
+		lapNumber    int
+	}{
+		{driverNumber: 1, lapNumber: 3},
+		{driverNumber: 44, lapNumber: 4},
+	}
+	personal := FastestLaps(laps)
+	if len(personal) != len(tests) {
+		t.Errorf("FastestLaps() returned %d drivers, want %d", len(personal), len(tests))
+	}
+	for _, tt := range tests {
+		if got := personal[tt.driverNumber].LapNumber; got != tt.lapNumber {
+			t.Errorf("FastestLaps() for driver %d = lap %d, want lap %d", tt.driverNumber, got, tt.lapNumber)
+		}
+	}
+
+	if _, ok := FastestLap(openf1go.LapsResponse{lap(1, 1, 0)}); ok {
+		t.Error("FastestLap() without timed laps reported a lap")
+	}
+}
+
+func TestSectorBests(t *testing.T) {
+	laps := openf1go.LapsResponse{
+		lap(1, 2, 100, 30, 40, 31),
+		lap(1, 3, 200, 29.5, 40.5, 30),
+		lap(44, 2, 101, 30.5, 39, 32),
+		{DriverNumber: 63, LapNumber: 2, LapDuration: 100, DurationSector1: 29}, // Missing sectors 2 and 3
+	}
+
+	personal := PersonalSectorBests(laps)
+	want := map[int]SectorBests{
+		1:  {Sector1: 29.5, Sector2: 40, Sector3: 30},
+		44: {Sector1: 30.5, Sector2: 39, Sector3: 32},
+		63: {Sector1: 29},
+	}
+	if !reflect.DeepEqual(personal, want) {
+		t.Errorf("PersonalSectorBests() = %v, want %v", personal, want)
+	}
+
+	if got := OverallSectorBests(laps); got != (SectorBests{Sector1: 29, Sector2: 39, Sector3: 30}) {
+		t.Errorf("OverallSectorBests() = %+v", got)
+	}
+
+	ideal := TheoreticalBests(laps)
+	if len(ideal) != 2 || math.Abs(ideal[1]-99.5) > 1e-9 || math.Abs(ideal[44]-101.5) > 1e-9 {
+		t.Errorf("TheoreticalBests() = %v, want 99.5 for driver 1 and 101.5 for driver 44", ideal)
+	}
+}
+
+func TestClassifySectors(t *testing.T) {
+	tests := []struct {
+		name string
+		laps openf1go.LapsResponse
+		want map[[2]int][3]SectorClass // Classes per driver and lap number
+	}{
+		{
+			name: "first lap sets every best",
+			laps: openf1go.LapsResponse{lap(1, 2, 100, 30, 40, 30)},
+			want: map[[2]int][3]SectorClass{{1, 2}: {SectorPurple, SectorPurple, SectorPurple}},
+		},
+		{
+			name: "judged in the order driven",
+			laps: openf1go.LapsResponse{
+				lap(1, 3, 200, 29, 41, 31), // Listed first but driven last
+				lap(1, 2, 100, 30, 40, 30),
+				lap(44, 2, 101, 31, 39, 32),
+			},
+			want: map[[2]int][3]SectorClass{
+				{1, 2}:  {SectorPurple, SectorPurple, SectorPurple},
+				{44, 2}: {SectorGreen, SectorPurple, SectorGreen},
+				{1, 3}:  {SectorPurple, SectorYellow, SectorYellow},
+			},
+		},
+		{
+			name: "equalling a best is no improvement",
+			laps: openf1go.LapsResponse{
+				lap(1, 2, 100, 30, 40, 30),
+				lap(44, 2, 101, 30, 41, 31),
+				lap(1, 3, 200, 30, 40, 30),
+			},
+			want: map[[2]int][3]SectorClass{
+				{1, 2}:  {SectorPurple, SectorPurple, SectorPurple},
+				{44, 2}: {SectorGreen, SectorGreen, SectorGreen},
+				{1, 3}:  {SectorYellow, SectorYellow, SectorYellow},
+			},
+		},
+		{
+			name: "missing sector",
+			laps: openf1go.LapsResponse{{DriverNumber: 1, LapNumber: 2, DateStart: lapStart, LapDuration: 100, DurationSector1: 30, DurationSector3: 30}},
+			want: map[[2]int][3]SectorClass{{1, 2}: {SectorPurple, SectorNone, SectorPurple}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[[2]int][3]SectorClass{}
+			for _, entry := range ClassifySectors(tt.laps) {
+				got[[2]int{entry.Lap.DriverNumber, entry.Lap.LapNumber}] = entry.Sectors
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClassifySectors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLapDeltas(t *testing.T) {
+	laps := openf1go.LapsResponse{
+		lap(1, 1, 0),
+		lap(1, 2, 100, 30, 40, 31),
+		lap(44, 2, 101, 30, 40, 32),
+		lap(1, 3, 201, 30, 40, 30),
+		lap(44, 3, 203, 30, 40, 31),
+	}
+
+	want := []LapDelta{
+		{DriverNumber: 1, LapNumber: 2, LapDuration: 101, ToPersonalBest: 1, ToFastest: 1},
+		{DriverNumber: 44, LapNumber: 2, LapDuration: 102, ToPersonalBest: 1, ToFastest: 2},
+		{DriverNumber: 1, LapNumber: 3, LapDuration: 100, ToPersonalBest: 0, ToFastest: 0},
+		{DriverNumber: 44, LapNumber: 3, LapDuration: 101, ToPersonalBest: 0, ToFastest: 1},
+	}
+
+	got := LapDeltas(laps)
+	if len(got) != len(want) {
+		t.Fatalf("LapDeltas() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		d := got[i]
+		if d.DriverNumber != want[i].DriverNumber || d.LapNumber != want[i].LapNumber ||
+			math.Abs(d.ToPersonalBest-want[i].ToPersonalBest) > 1e-9 || math.Abs(d.ToFastest-want[i].ToFastest) > 1e-9 {
+			t.Errorf("delta %d = %+v, want %+v", i, d, want[i])
+		}
+	}
+}
+
+func TestSortByStart(t *testing.T) {
+	tests := []struct {
+		name string
+		laps openf1go.LapsResponse
+		want [][2]int
+	}{
+		{
+			name: "by start time",
+			laps: openf1go.LapsResponse{lap(1, 3, 200), lap(44, 2, 101), lap(1, 2, 100)},
+			want: [][2]int{{1, 2}, {44, 2}, {1, 3}},
+		},
+		{
+			name: "without a start time first",
+			laps: openf1go.LapsResponse{lap(1, 2, 100), {DriverNumber: 44, LapNumber: 1}, lap(44, 2, 99), {DriverNumber: 1, LapNumber: 1}},
+			want: [][2]int{{1, 1}, {44, 1}, {44, 2}, {1, 2}},
+		},
+		{
+			name: "same start time",
+			laps: openf1go.LapsResponse{lap(44, 2, 100), lap(1, 2, 100)},
+			want: [][2]int{{1, 2}, {44, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			laps := append(openf1go.LapsResponse{}, tt.laps...)
+			sortByStart(laps)
+			if got := lapNumbers(laps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortByStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
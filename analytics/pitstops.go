@@ -0,0 +1,260 @@
+package analytics
+
+// Pit stop time loss and undercut/overcut detection combining pit, position, interval and lap data.
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// PitBattleKind identifies the type of pit stop battle
+type PitBattleKind string
+
+const (
+	Undercut PitBattleKind = "undercut" // The car behind pitted first to get ahead
+	Overcut  PitBattleKind = "overcut"  // The car behind stayed out longer to get ahead
+)
+
+// PitStopOptions configures pit stop analysis
+type PitStopOptions struct {
+	Window        float64 // Maximum gap in seconds between two cars before the first stop
+	MaxLapsApart  int     // Maximum number of laps between the two stops of a battle
+	ReferenceLaps int     // Number of laps either side of a stop used for the reference pace
+}
+
+// DefaultPitStopOptions returns the options used when none are provided
+func DefaultPitStopOptions() PitStopOptions {
+	return PitStopOptions{Window: 3, MaxLapsApart: 5, ReferenceLaps: 5}
+}
+
+// PitStopLoss represents the time and positions lost by a single pit stop
+type PitStopLoss struct {
+	Pit            openf1go.Pit // The pit stop
+	InLap          float64      // Duration of the lap entering the pit lane in seconds
+	OutLap         float64      // Duration of the lap leaving the pit lane in seconds
+	ReferencePace  float64      // Median clean lap time around the stop in seconds
+	Loss           float64      // Time lost compared to two reference laps in seconds
+	PositionBefore int          // Position when entering the pit lane
+	PositionAfter  int          // Position once the driver completed the out lap
+}
+
+// PositionsGained returns the number of positions gained by the stop, negative when positions were lost
+func (p PitStopLoss) PositionsGained() int {
+	if p.PositionBefore == 0 || p.PositionAfter == 0 {
+		return 0
+	}
+	return p.PositionBefore - p.PositionAfter
+}
+
+// PitBattle represents an undercut or overcut attempt between two cars
+type PitBattle struct {
+	Kind           PitBattleKind // Undercut or overcut
+	Attacker       int           // Driver number of the car trying to get ahead
+	Defender       int           // Driver number of the car ahead before the stops
+	AttackerPitLap int           // Lap on which the attacker pitted
+	DefenderPitLap int           // Lap on which the defender pitted
+	GapBefore      float64       // Gap in seconds between the cars before the first stop
+	Succeeded      bool          // Indicates if the attacker was ahead once the second car completed its out lap
+}
+
+// PitStopReport represents the pit stop analysis of a session
+type PitStopReport struct {
+	SessionKey int           // Identifier for the session
+	CircuitKey int           // Identifier for the circuit
+	MedianLoss float64       // Median pit lane loss in seconds for the circuit
+	MeanLoss   float64       // Mean pit lane loss in seconds for the circuit
+	Stops      []PitStopLoss // Loss of each stop ordered by time
+	Battles    []PitBattle   // Undercut and overcut attempts ordered by the first stop
+}
+
+// AnalysePitStops computes the pit lane loss of every stop and detects undercut and overcut attempts in a session
+func AnalysePitStops(session openf1go.Session, positions openf1go.PostionsResponse, intervals openf1go.IntervalsResponse, laps openf1go.LapsResponse, pits openf1go.PitResponse, opts PitStopOptions) PitStopReport {
+	timeline := openf1go.NewSessionTimeline(positions, intervals, laps)
+
+	report := PitStopReport{
+		SessionKey: session.SessionKey,
+		CircuitKey: session.CircuitKey,
+		Stops:      []PitStopLoss{},
+		Battles:    []PitBattle{},
+	}
+
+	// Index laps by driver and lap number
+	byLap := map[[2]int]openf1go.Lap{}
+	for _, lap := range laps {
+		byLap[[2]int{lap.DriverNumber, lap.LapNumber}] = lap
+	}
+	clean := CleanLaps(laps)
+
+	sorted := append(openf1go.PitResponse{}, pits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	var losses []float64
+	for _, pit := range sorted {
+		stop := PitStopLoss{
+			Pit:            pit,
+			InLap:          byLap[[2]int{pit.DriverNumber, pit.LapNumber}].LapDuration,
+			OutLap:         byLap[[2]int{pit.DriverNumber, pit.LapNumber + 1}].LapDuration,
+			ReferencePace:  referencePace(clean, pit, opts.ReferenceLaps),
+			PositionBefore: positionAt(timeline, pit.DriverNumber, pit.Date),
+			PositionAfter:  positionAt(timeline, pit.DriverNumber, outLapEnd(byLap, pit)),
+		}
+		if stop.InLap > 0 && stop.OutLap > 0 && stop.ReferencePace > 0 {
+			stop.Loss = stop.InLap + stop.OutLap - 2*stop.ReferencePace
+			losses = append(losses, stop.Loss)
+		}
+		report.Stops = append(report.Stops, stop)
+	}
+
+	report.MedianLoss = median(losses)
+	for _, loss := range losses {
+		report.MeanLoss += loss / float64(len(losses))
+	}
+
+	report.Battles = detectPitBattles(timeline, byLap, sorted, opts)
+
+	return report
+}
+
+// detectPitBattles finds cars within the window of each other where one pitted first and the other followed
+func detectPitBattles(timeline *openf1go.SessionTimeline, byLap map[[2]int]openf1go.Lap, pits openf1go.PitResponse, opts PitStopOptions) []PitBattle {
+	battles := []PitBattle{}
+
+	for _, first := range pits {
+		state := timeline.StateAt(first.Date)
+		self, ok := findEntry(state, first.DriverNumber)
+		if !ok {
+			continue
+		}
+
+		for _, other := range state {
+			// Only the cars directly ahead and behind are candidates
+			if other.Position != self.Position-1 && other.Position != self.Position+1 {
+				continue
+			}
+
+			gap, ok := gapBetween(self, other)
+			if !ok || gap > opts.Window {
+				continue
+			}
+
+			// The other car must pit later within the allowed number of laps
+			second, ok := nextPit(pits, other.DriverNumber, first.LapNumber, opts.MaxLapsApart)
+			if !ok {
+				continue
+			}
+
+			battle := PitBattle{GapBefore: gap}
+			if other.Position < self.Position {
+				battle.Kind = Undercut
+				battle.Attacker, battle.Defender = first.DriverNumber, other.DriverNumber
+				battle.AttackerPitLap, battle.DefenderPitLap = first.LapNumber, second.LapNumber
+			} else {
+				battle.Kind = Overcut
+				battle.Attacker, battle.Defender = other.DriverNumber, first.DriverNumber
+				battle.AttackerPitLap, battle.DefenderPitLap = second.LapNumber, first.LapNumber
+			}
+
+			// Judge the outcome once the second car has completed its out lap
+			after := timeline.StateAt(outLapEnd(byLap, second))
+			attacker, okA := findEntry(after, battle.Attacker)
+			defender, okD := findEntry(after, battle.Defender)
+			battle.Succeeded = okA && okD && attacker.Position < defender.Position
+
+			battles = append(battles, battle)
+		}
+	}
+
+	return battles
+}
+
+// nextPit returns the first stop of a driver after the given lap and within maxLaps laps of it
+func nextPit(pits openf1go.PitResponse, driverNumber, lap, maxLaps int) (openf1go.Pit, bool) {
+	for _, pit := range pits {
+		if pit.DriverNumber == driverNumber && pit.LapNumber > lap && pit.LapNumber <= lap+maxLaps {
+			return pit, true
+		}
+	}
+	return openf1go.Pit{}, false
+}
+
+// gapBetween returns the time gap in seconds between two classification entries
+func gapBetween(a, b openf1go.ClassificationEntry) (float64, bool) {
+	ga, gb := a.GapToLeader, b.GapToLeader
+	if ga.Valid && gb.Valid && ga.Laps == 0 && gb.Laps == 0 {
+		return math.Abs(ga.Seconds - gb.Seconds), true
+	}
+
+	// Fall back to the interval of the car behind
+	behind := a
+	if b.Position > a.Position {
+		behind = b
+	}
+	if behind.Interval.Valid && behind.Interval.Laps == 0 {
+		return behind.Interval.Seconds, true
+	}
+
+	return 0, false
+}
+
+// findEntry returns the classification entry of a driver
+func findEntry(classification openf1go.Classification, driverNumber int) (openf1go.ClassificationEntry, bool) {
+	for _, entry := range classification {
+		if entry.DriverNumber == driverNumber {
+			return entry, true
+		}
+	}
+	return openf1go.ClassificationEntry{}, false
+}
+
+// positionAt returns the position of a driver at the given time, zero when unknown
+func positionAt(timeline *openf1go.SessionTimeline, driverNumber int, at time.Time) int {
+	entry, _ := findEntry(timeline.StateAt(at), driverNumber)
+	return entry.Position
+}
+
+// outLapEnd returns the time the pitting driver completed the out lap following a stop, taken as the start
+// of the driver's next lap or the start of the out lap plus its duration.
+// Falls back to the time of the stop when the driver's laps have no timing data.
+func outLapEnd(byLap map[[2]int]openf1go.Lap, pit openf1go.Pit) time.Time {
+	var end time.Time
+	if next := byLap[[2]int{pit.DriverNumber, pit.LapNumber + 2}]; !next.DateStart.IsZero() {
+		end = next.DateStart
+	} else if out := byLap[[2]int{pit.DriverNumber, pit.LapNumber + 1}]; !out.DateStart.IsZero() && out.LapDuration > 0 {
+		end = out.DateStart.Add(time.Duration(out.LapDuration * float64(time.Second)))
+	}
+
+	if end.After(pit.Date) {
+		return end
+	}
+	return pit.Date
+}
+
+// referencePace returns the median clean lap time of a driver within the given number of laps of a stop
+func referencePace(clean openf1go.LapsResponse, pit openf1go.Pit, around int) float64 {
+	var times []float64
+	for _, lap := range clean {
+		if lap.DriverNumber == pit.DriverNumber && lap.LapNumber >= pit.LapNumber-around && lap.LapNumber <= pit.LapNumber+1+around {
+			times = append(times, lap.LapDuration)
+		}
+	}
+	return median(times)
+}
+
+// median returns the median of the values, zero when there are none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+var raceStart = time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// at returns the time the given number of seconds into the race
+func at(seconds float64) time.Time {
+	return raceStart.Add(time.Duration(seconds * float64(time.Second)))
+}
+
+func TestOutLapEnd(t *testing.T) {
+	pit := openf1go.Pit{DriverNumber: 44, LapNumber: 10, Date: at(1025)}
+
+	tests := []struct {
+		name string
+		laps openf1go.LapsResponse
+		want time.Time
+	}{
+		{
+			name: "start of the next lap",
+			laps: openf1go.LapsResponse{
+				{DriverNumber: 44, LapNumber: 11, DateStart: at(1030), LapDuration: 118},
+				{DriverNumber: 44, LapNumber: 12, DateStart: at(1150)},
+			},
+			want: at(1150),
+		},
+		{
+			name: "out lap duration",
+			laps: openf1go.LapsResponse{{DriverNumber: 44, LapNumber: 11, DateStart: at(1030), LapDuration: 120}},
+			want: at(1150),
+		},
+		{
+			name: "other driver's laps are ignored",
+			laps: openf1go.LapsResponse{{DriverNumber: 1, LapNumber: 12, DateStart: at(1100)}},
+			want: at(1025),
+		},
+		{
+			name: "no timing data",
+			want: at(1025),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			byLap := map[[2]int]openf1go.Lap{}
+			for _, lap := range tt.laps {
+				byLap[[2]int{lap.DriverNumber, lap.LapNumber}] = lap
+			}
+			if got := outLapEnd(byLap, pit); !got.Equal(tt.want) {
+				t.Errorf("outLapEnd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalysePitStopsPositionAfter(t *testing.T) {
+	// The leader laps in 100s, driver 44 runs 30s behind and loses 20s on its out lap
+	laps := openf1go.LapsResponse{}
+	for lap := 1; lap <= 13; lap++ {
+		laps = append(laps, openf1go.Lap{DriverNumber: 1, LapNumber: lap, DateStart: at(float64(lap-1) * 100), LapDuration: 100})
+
+		start := 30 + float64(lap-1)*100
+		if lap > 11 {
+			start += 20
+		}
+		duration := 100.0
+		if lap == 11 {
+			duration = 120
+		}
+		laps = append(laps, openf1go.Lap{DriverNumber: 44, LapNumber: lap, DateStart: at(start), LapDuration: duration, IsPitOutLap: lap == 11})
+	}
+
+	// Driver 44 drops to fifth in the pit lane and recovers to third by the end of its out lap,
+	// after the leader has already completed lap 11
+	positions := openf1go.PostionsResponse{
+		{DriverNumber: 1, Position: 1, Date: at(0)},
+		{DriverNumber: 44, Position: 2, Date: at(0)},
+		{DriverNumber: 44, Position: 5, Date: at(1040)},
+		{DriverNumber: 44, Position: 3, Date: at(1140)},
+	}
+	pits := openf1go.PitResponse{{DriverNumber: 44, LapNumber: 10, Date: at(1025), PitDuration: 22}}
+
+	report := AnalysePitStops(openf1go.Session{SessionKey: 9158}, positions, nil, laps, pits, DefaultPitStopOptions())
+	if len(report.Stops) != 1 {
+		t.Fatalf("AnalysePitStops() returned %d stops, want 1", len(report.Stops))
+	}
+
+	stop := report.Stops[0]
+	if stop.PositionBefore != 2 || stop.PositionAfter != 3 {
+		t.Errorf("positions = %d → %d, want 2 → 3", stop.PositionBefore, stop.PositionAfter)
+	}
+	if stop.Loss != 20 {
+		t.Errorf("Loss = %v, want 20", stop.Loss)
+	}
+}
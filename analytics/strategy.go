@@ -0,0 +1,174 @@
+package analytics
+
+// Stint and tyre strategy analysis combining stints, laps and pit stops.
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// DefaultFuelCorrection is the lap time in seconds a car typically gains per lap as fuel burns off
+const DefaultFuelCorrection = 0.06
+
+// StintAnalysis represents the derived pace of a single stint
+type StintAnalysis struct {
+	Stint            openf1go.Stint        // The analysed stint
+	Laps             openf1go.LapsResponse // Clean laps driven during the stint
+	AveragePace      float64               // Average clean lap time in seconds
+	DegradationSlope float64               // Fuel corrected lap time change in seconds per lap of tyre age
+}
+
+// DriverStrategy represents the strategy of a single driver
+type DriverStrategy struct {
+	DriverNumber int             // Driver's unique number
+	StintCount   int             // Number of stints driven
+	Stints       []StintAnalysis // Analysis of each stint ordered by stint number
+	Summary      string          // Summary such as "M(18) → H(34)"
+}
+
+// TyreAges returns the tyre age for every lap covered by the stints keyed by driver number and lap number.
+// Ongoing stints, without an end lap yet, run to the latest lap any stint reaches.
+func TyreAges(stints openf1go.StintsReponse) map[int]map[int]int {
+	ages := map[int]map[int]int{}
+
+	latest := 0
+	for _, stint := range stints {
+		latest = max(latest, stint.LapStart, stint.LapEnd)
+	}
+
+	for _, stint := range stints {
+		if ages[stint.DriverNumber] == nil {
+			ages[stint.DriverNumber] = map[int]int{}
+		}
+		end := stint.LapEnd
+		if end == 0 {
+			end = latest
+		}
+		for lap := stint.LapStart; lap <= end; lap++ {
+			ages[stint.DriverNumber][lap] = stint.TyreAgeAtStart + lap - stint.LapStart
+		}
+	}
+
+	return ages
+}
+
+// compoundLetters maps compound names to the letter used in strategy summaries
+var compoundLetters = map[string]string{
+	"SOFT":         "S",
+	"MEDIUM":       "M",
+	"HARD":         "H",
+	"INTERMEDIATE": "I",
+	"WET":          "W",
+}
+
+// StrategySummary formats a driver's stints as compound letters with stint lengths, e.g. "M(18) → H(34)".
+// Unknown compounds are shown as "?" and ongoing stints without a length, e.g. "M(18) → H".
+func StrategySummary(stints openf1go.StintsReponse) string {
+	sorted := append(openf1go.StintsReponse{}, stints...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StintNumber < sorted[j].StintNumber })
+
+	parts := make([]string, 0, len(sorted))
+	for _, stint := range sorted {
+		letter, ok := compoundLetters[strings.ToUpper(stint.Compound)]
+		if !ok {
+			letter = "?"
+		}
+		// Ongoing stints have no end lap yet and are shown without a length
+		if stint.LapEnd == 0 {
+			parts = append(parts, letter)
+			continue
+		}
+		parts = append(parts, letter+"("+strconv.Itoa(stint.LapEnd-stint.LapStart+1)+")")
+	}
+
+	return strings.Join(parts, " → ")
+}
+
+// AnalyseStint computes the average pace and fuel corrected degradation of a stint.
+// Laps are filtered to the driver and lap range of the stint, open ended while the stint is ongoing,
+// and pit laps are excluded.
+func AnalyseStint(stint openf1go.Stint, laps openf1go.LapsResponse, pits openf1go.PitResponse, fuelCorrection float64) StintAnalysis {
+	analysis := StintAnalysis{Stint: stint, Laps: openf1go.LapsResponse{}}
+
+	// Laps on which the driver entered the pit lane are not representative
+	pitLaps := map[int]bool{}
+	for _, pit := range pits {
+		if pit.DriverNumber == stint.DriverNumber {
+			pitLaps[pit.LapNumber] = true
+		}
+	}
+
+	var ages, times []float64
+	for _, lap := range CleanLaps(laps) {
+		if lap.DriverNumber != stint.DriverNumber || lap.LapNumber < stint.LapStart || (stint.LapEnd != 0 && lap.LapNumber > stint.LapEnd) || pitLaps[lap.LapNumber] {
+			continue
+		}
+		analysis.Laps = append(analysis.Laps, lap)
+		analysis.AveragePace += lap.LapDuration
+
+		// Add back the time gained from burning fuel so only tyre wear remains
+		ages = append(ages, float64(stint.TyreAgeAtStart+lap.LapNumber-stint.LapStart))
+		times = append(times, lap.LapDuration+fuelCorrection*float64(lap.LapNumber-1))
+	}
+
+	if len(analysis.Laps) > 0 {
+		analysis.AveragePace /= float64(len(analysis.Laps))
+	}
+	analysis.DegradationSlope, _ = linearFit(ages, times)
+
+	return analysis
+}
+
+// Strategies analyses the stints of every driver keyed by driver number
+func Strategies(stints openf1go.StintsReponse, laps openf1go.LapsResponse, pits openf1go.PitResponse, fuelCorrection float64) map[int]DriverStrategy {
+	byDriver := map[int]openf1go.StintsReponse{}
+	for _, stint := range stints {
+		byDriver[stint.DriverNumber] = append(byDriver[stint.DriverNumber], stint)
+	}
+
+	strategies := map[int]DriverStrategy{}
+	for driverNumber, driverStints := range byDriver {
+		sort.Slice(driverStints, func(i, j int) bool { return driverStints[i].StintNumber < driverStints[j].StintNumber })
+
+		strategy := DriverStrategy{
+			DriverNumber: driverNumber,
+			StintCount:   len(driverStints),
+			Summary:      StrategySummary(driverStints),
+		}
+		for _, stint := range driverStints {
+			strategy.Stints = append(strategy.Stints, AnalyseStint(stint, laps, pits, fuelCorrection))
+		}
+		strategies[driverNumber] = strategy
+	}
+
+	return strategies
+}
+
+// linearFit returns the least squares slope and intercept of y over x.
+// Returns zeros when fewer than two distinct x values are available.
+func linearFit(x, y []float64) (slope, intercept float64) {
+	n := float64(len(x))
+	if len(x) < 2 || len(x) != len(y) {
+		return 0, 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0
+	}
+
+	slope = (n*sumXY - sumX*sumY) / denominator
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept
+}
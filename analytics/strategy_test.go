@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func TestStrategySummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		stints openf1go.StintsReponse
+		want   string
+	}{
+		{
+			name: "finished race",
+			stints: openf1go.StintsReponse{
+				{StintNumber: 2, Compound: "HARD", LapStart: 19, LapEnd: 62},
+				{StintNumber: 1, Compound: "MEDIUM", LapStart: 1, LapEnd: 18},
+			},
+			want: "M(18) → H(44)",
+		},
+		{
+			name: "ongoing stint",
+			stints: openf1go.StintsReponse{
+				{StintNumber: 1, Compound: "MEDIUM", LapStart: 1, LapEnd: 18},
+				{StintNumber: 2, Compound: "HARD", LapStart: 19},
+			},
+			want: "M(18) → H",
+		},
+		{
+			name:   "unknown compound",
+			stints: openf1go.StintsReponse{{StintNumber: 1, Compound: "UNKNOWN", LapStart: 1, LapEnd: 5}},
+			want:   "?(5)",
+		},
+		{
+			name: "no stints",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrategySummary(tt.stints); got != tt.want {
+				t.Errorf("StrategySummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTyreAges(t *testing.T) {
+	tests := []struct {
+		name   string
+		stints openf1go.StintsReponse
+		want   map[int]map[int]int
+	}{
+		{
+			name: "finished stints",
+			stints: openf1go.StintsReponse{
+				{DriverNumber: 1, StintNumber: 1, LapStart: 1, LapEnd: 2, TyreAgeAtStart: 3},
+				{DriverNumber: 1, StintNumber: 2, LapStart: 3, LapEnd: 4},
+			},
+			want: map[int]map[int]int{1: {1: 3, 2: 4, 3: 0, 4: 1}},
+		},
+		{
+			name: "ongoing stint",
+			stints: openf1go.StintsReponse{
+				{DriverNumber: 1, StintNumber: 1, LapStart: 1, LapEnd: 2},
+				{DriverNumber: 1, StintNumber: 2, LapStart: 3, TyreAgeAtStart: 2},
+				{DriverNumber: 44, StintNumber: 1, LapStart: 1, LapEnd: 5},
+			},
+			want: map[int]map[int]int{1: {1: 0, 2: 1, 3: 2, 4: 3, 5: 4}, 44: {1: 0, 2: 1, 3: 2, 4: 3, 5: 4}},
+		},
+		{
+			name:   "ongoing first stint",
+			stints: openf1go.StintsReponse{{DriverNumber: 1, StintNumber: 1, LapStart: 1}},
+			want:   map[int]map[int]int{1: {1: 0}},
+		},
+		{
+			name: "no stints",
+			want: map[int]map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TyreAges(tt.stints); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TyreAges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyseStint(t *testing.T) {
+	// Lap times grow by 0.1s per lap of tyre age on top of the fuel effect
+	laps := openf1go.LapsResponse{}
+	for lap := 1; lap <= 10; lap++ {
+		laps = append(laps, openf1go.Lap{DriverNumber: 1, LapNumber: lap, LapDuration: 100 + 0.1*float64(lap) - 0.06*float64(lap-1)})
+	}
+	laps[4].LapDuration = 120 // Pit in lap, excluded through the pit record
+	pits := openf1go.PitResponse{{DriverNumber: 1, LapNumber: 5}}
+
+	tests := []struct {
+		name  string
+		stint openf1go.Stint
+		laps  int
+	}{
+		{name: "finished stint", stint: openf1go.Stint{DriverNumber: 1, LapStart: 1, LapEnd: 8}, laps: 7},
+		{name: "ongoing stint", stint: openf1go.Stint{DriverNumber: 1, LapStart: 6}, laps: 5},
+		{name: "other driver", stint: openf1go.Stint{DriverNumber: 44, LapStart: 1, LapEnd: 10}, laps: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyseStint(tt.stint, laps, pits, DefaultFuelCorrection)
+			if len(analysis.Laps) != tt.laps {
+				t.Fatalf("AnalyseStint() used %d laps, want %d", len(analysis.Laps), tt.laps)
+			}
+			if tt.laps > 1 && math.Abs(analysis.DegradationSlope-0.1) > 1e-9 {
+				t.Errorf("DegradationSlope = %v, want 0.1", analysis.DegradationSlope)
+			}
+		})
+	}
+}
+
+func TestLinearFit(t *testing.T) {
+	tests := []struct {
+		name             string
+		x, y             []float64
+		slope, intercept float64
+	}{
+		{name: "line", x: []float64{1, 2, 3}, y: []float64{3, 5, 7}, slope: 2, intercept: 1},
+		{name: "single point", x: []float64{1}, y: []float64{3}},
+		{name: "same x", x: []float64{2, 2}, y: []float64{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slope, intercept := linearFit(tt.x, tt.y)
+			if math.Abs(slope-tt.slope) > 1e-9 || math.Abs(intercept-tt.intercept) > 1e-9 {
+				t.Errorf("linearFit() = %v, %v, want %v, %v", slope, intercept, tt.slope, tt.intercept)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package openf1go
+
+// Downloads every endpoint of a session into a local archive of compressed NDJSON files with a manifest.
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ArchiveEndpoints lists the endpoints written to a session archive, in download order
+var ArchiveEndpoints = []string{
+	"meetings", "sessions", "drivers", "laps", "car_data", "location", "intervals",
+	"position", "pit", "stints", "race_control", "team_radio", "weather",
+}
+
+// chunkedEndpoints hold several samples per second and are downloaded per driver and time window
+var chunkedEndpoints = map[string]bool{"car_data": true, "location": true}
+
+// ManifestFile is the name of the manifest written to the root of a session archive
+const ManifestFile = "manifest.json"
+
+// DownloadOptions configures the pacing of DownloadSession and of syncs into a store
+type DownloadOptions struct {
+	RequestInterval time.Duration // Minimum time between two requests
+	Chunk           time.Duration // Length of the time windows car data and location are downloaded in
+}
+
+// DefaultDownloadOptions returns the pacing used when none is provided, within the API's public rate limits
+func DefaultDownloadOptions() DownloadOptions {
+	return DownloadOptions{
+		RequestInterval: 400 * time.Millisecond,
+		Chunk:           30 * time.Minute,
+	}
+}
+
+// WithDefaults returns the options with zero fields set to those of DefaultDownloadOptions,
+// or ErrInvalidDownloadOptions when a field is negative
+func (o DownloadOptions) WithDefaults() (DownloadOptions, error) {
+	if o.RequestInterval < 0 || o.Chunk < 0 {
+		return DownloadOptions{}, ErrInvalidDownloadOptions
+	}
+
+	defaults := DefaultDownloadOptions()
+	if o.RequestInterval == 0 {
+		o.RequestInterval = defaults.RequestInterval
+	}
+	if o.Chunk == 0 {
+		o.Chunk = defaults.Chunk
+	}
+	return o, nil
+}
+
+// downloadPadding widens the session window as cars report data before the start and after the end of a session
+const downloadPadding = time.Hour
+
+// downloadRetries is the number of times a rate limited request is retried
+const downloadRetries = 3
+
+// Manifest describes the contents of a session archive
+type Manifest struct {
+	SessionKey   int                    `json:"session_key"`   // Session the archive holds
+	MeetingKey   int                    `json:"meeting_key"`   // Meeting the session belongs to
+	Session      Session                `json:"session"`       // The archived session
+	DownloadedAt time.Time              `json:"downloaded_at"` // Time the download completed
+	Endpoints    map[string]ArchiveFile `json:"endpoints"`     // File written for each endpoint
+}
+
+// ArchiveFile describes the file holding the records of one endpoint
+type ArchiveFile struct {
+	File    string     `json:"file"`           // Name of the gzip compressed NDJSON file relative to the archive
+	Records int        `json:"records"`        // Number of records in the file
+	From    *time.Time `json:"from,omitempty"` // Earliest record date, absent when records carry no date
+	To      *time.Time `json:"to,omitempty"`   // Latest record date, absent when records carry no date
+	SHA256  string     `json:"sha256"`         // Hex encoded SHA-256 checksum of the file
+}
+
+// ArchiveFileName returns the name of the file holding the records of an endpoint
+func ArchiveFileName(endpoint string) string {
+	return endpoint + ".ndjson.gz"
+}
+
+// ReadManifest reads the manifest of a session archive
+func ReadManifest(dir string) (Manifest, error) {
+	var manifest Manifest
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// DownloadSession downloads every endpoint of a session into dir, one gzip compressed NDJSON file per endpoint,
+// and writes a manifest recording record counts, time ranges and checksums.
+// Requests are spaced by opts.RequestInterval and rate limited requests are retried.
+// Zero options fall back to DefaultDownloadOptions.
+func (c *Client) DownloadSession(ctx context.Context, sessionKey int, dir string, opts DownloadOptions) (Manifest, error) {
+	if sessionKey == 0 {
+		return Manifest{}, ErrSessionKeyMissing
+	}
+	opts, err := opts.WithDefaults()
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Manifest{}, err
+	}
+
+	d := &downloader{client: c, ticker: time.NewTicker(opts.RequestInterval), chunk: opts.Chunk}
+	defer d.ticker.Stop()
+
+	// Find the session to learn its meeting, time window and drivers
+	sessionArgs := []Arg{{Key: "session_key", Value: strconv.Itoa(sessionKey)}}
+	sessions, err := d.fetch(ctx, "sessions", sessionArgs)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if len(sessions) == 0 {
+		return Manifest{}, ErrSessionNotFound
+	}
+	var session Session
+	if err := json.Unmarshal(sessions[0], &session); err != nil {
+		return Manifest{}, err
+	}
+
+	drivers, err := d.fetch(ctx, "drivers", sessionArgs)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var driverResponse DriversResponse
+	for _, raw := range drivers {
+		var driver Driver
+		if err := json.Unmarshal(raw, &driver); err != nil {
+			return Manifest{}, err
+		}
+		driverResponse = append(driverResponse, driver)
+	}
+
+	manifest := Manifest{SessionKey: sessionKey, MeetingKey: session.MeetingKey, Session: session, Endpoints: map[string]ArchiveFile{}}
+
+	for _, endpoint := range ArchiveEndpoints {
+		w, err := newArchiveWriter(filepath.Join(dir, ArchiveFileName(endpoint)))
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		switch {
+		case endpoint == "sessions":
+			err = w.write(sessions)
+		case endpoint == "drivers":
+			err = w.write(drivers)
+		case endpoint == "meetings":
+			err = d.fetchTo(ctx, w, endpoint, []Arg{{Key: "meeting_key", Value: strconv.Itoa(session.MeetingKey)}})
+		case chunkedEndpoints[endpoint]:
+			err = d.fetchChunked(ctx, w, endpoint, session, driverResponse)
+		default:
+			err = d.fetchTo(ctx, w, endpoint, sessionArgs)
+		}
+		if err != nil {
+			w.f.Close()
+			return Manifest{}, err
+		}
+
+		file, err := w.close()
+		if err != nil {
+			return Manifest{}, err
+		}
+		manifest.Endpoints[endpoint] = file
+	}
+
+	// Write the manifest last so an archive with a manifest is always complete
+	manifest.DownloadedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644); err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// downloader spaces and retries the requests of a session download
+type downloader struct {
+	client *Client       // Client making the requests
+	ticker *time.Ticker  // Paces requests to the request interval
+	chunk  time.Duration // Length of the time windows chunked endpoints are downloaded in
+}
+
+// fetch waits for the next request slot and returns the raw records of an endpoint matching the args
+func (d *downloader) fetch(ctx context.Context, endpoint string, args []Arg) ([]json.RawMessage, error) {
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-d.ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		resp, err := d.client.Raw(ctx, endpoint, args)
+		if errors.Is(err, ErrRateLimited) && attempt < downloadRetries {
+			// Back off for a few request slots before retrying
+			for i := 0; i < 1<<attempt; i++ {
+				select {
+				case <-d.ticker.C:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var records []json.RawMessage
+		if err := json.Unmarshal(resp, &records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+}
+
+// fetchTo fetches the records of an endpoint matching the args and writes them to the archive file
+func (d *downloader) fetchTo(ctx context.Context, w *archiveWriter, endpoint string, args []Arg) error {
+	records, err := d.fetch(ctx, endpoint, args)
+	if err != nil {
+		return err
+	}
+	return w.write(records)
+}
+
+// fetchChunked downloads an endpoint per driver and time window across the session,
+// writing each window to the archive file as it arrives
+func (d *downloader) fetchChunked(ctx context.Context, w *archiveWriter, endpoint string, session Session, drivers DriversResponse) error {
+	start := session.DateStart.Add(-downloadPadding)
+	end := session.DateEnd.Add(downloadPadding)
+
+	for _, driver := range drivers {
+		for from := start; from.Before(end); from = from.Add(d.chunk) {
+			args := []Arg{
+				{Key: "session_key", Value: strconv.Itoa(session.SessionKey)},
+				{Key: "driver_number", Value: strconv.Itoa(driver.DriverNumber)},
+				{Key: "date>=", Value: from.UTC().Format(time.RFC3339Nano)},
+				{Key: "date<", Value: from.Add(d.chunk).UTC().Format(time.RFC3339Nano)},
+			}
+
+			if err := d.fetchTo(ctx, w, endpoint, args); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// archiveWriter writes records to an archive file as gzip compressed NDJSON while describing the file
+type archiveWriter struct {
+	f    *os.File     // File being written
+	hash hash.Hash    // Checksum of the compressed bytes written
+	gz   *gzip.Writer // Compresses into the file and the checksum
+	file ArchiveFile  // Description of the records written so far
+	line bytes.Buffer // Reused buffer holding the record being written
+}
+
+// newArchiveWriter creates the archive file at path
+func newArchiveWriter(path string) (*archiveWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &archiveWriter{f: f, hash: sha256.New(), file: ArchiveFile{File: filepath.Base(path)}}
+	w.gz = gzip.NewWriter(io.MultiWriter(f, w.hash))
+	return w, nil
+}
+
+// write appends records to the archive file
+func (w *archiveWriter) write(records []json.RawMessage) error {
+	for _, record := range records {
+		// Compact each record onto a single line
+		w.line.Reset()
+		if err := json.Compact(&w.line, record); err != nil {
+			return err
+		}
+		w.line.WriteByte('\n')
+		if _, err := w.gz.Write(w.line.Bytes()); err != nil {
+			return err
+		}
+		w.file.Records++
+
+		// Extend the time range with the date of the record
+		if date, ok := recordDate(record); ok {
+			if w.file.From == nil || date.Before(*w.file.From) {
+				w.file.From = &date
+			}
+			if w.file.To == nil || date.After(*w.file.To) {
+				w.file.To = &date
+			}
+		}
+	}
+
+	return nil
+}
+
+// close flushes and closes the archive file and describes the written file
+func (w *archiveWriter) close() (ArchiveFile, error) {
+	if err := w.gz.Close(); err != nil {
+		w.f.Close()
+		return ArchiveFile{}, err
+	}
+	if err := w.f.Close(); err != nil {
+		return ArchiveFile{}, err
+	}
+
+	w.file.SHA256 = hex.EncodeToString(w.hash.Sum(nil))
+	return w.file, nil
+}
+
+// recordDate returns the date of a raw record, taken from its date or date_start field
+func recordDate(record json.RawMessage) (time.Time, bool) {
+	var dates struct {
+		Date      string `json:"date"`       // Date of most records
+		DateStart string `json:"date_start"` // Date of laps, sessions and meetings
+	}
+	if err := json.Unmarshal(record, &dates); err != nil {
+		return time.Time{}, false
+	}
+
+	for _, s := range []string{dates.Date, dates.DateStart} {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,136 @@
+package openf1go_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+func TestDownloadSession(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	tests := []struct {
+		name  string
+		chunk time.Duration
+	}{
+		{name: "one window", chunk: 24 * time.Hour},
+		{name: "many windows", chunk: 10 * time.Minute},
+	}
+
+	want := map[string]int{
+		"meetings": 1, "sessions": 1, "drivers": 3, "laps": 18, "car_data": 80, "location": 80, "intervals": 30,
+		"position": 5, "pit": 1, "stints": 4, "race_control": 8, "team_radio": 2, "weather": 6,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := openf1go.DownloadOptions{RequestInterval: time.Millisecond, Chunk: tt.chunk}
+
+			manifest, err := client.DownloadSession(context.Background(), 9158, dir, opts)
+			if err != nil {
+				t.Fatalf("DownloadSession() returned %v", err)
+			}
+
+			for endpoint, records := range want {
+				file, ok := manifest.Endpoints[endpoint]
+				if !ok {
+					t.Errorf("manifest has no %s file", endpoint)
+					continue
+				}
+				if file.Records != records {
+					t.Errorf("%s holds %d records, want %d", endpoint, file.Records, records)
+				}
+
+				data, err := os.ReadFile(filepath.Join(dir, file.File))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != file.SHA256 {
+					t.Errorf("%s checksum does not match the manifest", endpoint)
+				}
+			}
+
+			if car := manifest.Endpoints["car_data"]; car.From == nil || car.To == nil || !car.From.Before(*car.To) {
+				t.Errorf("car_data time range = %v - %v", car.From, car.To)
+			}
+
+			// The archive answers the same queries as the API
+			source, err := openf1go.NewArchiveDataSource(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			laps, err := openf1go.New(openf1go.WithDataSource(source)).GetLaps(openf1go.Lap{SessionKey: 9158, DriverNumber: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(laps) != 6 {
+				t.Errorf("GetLaps() from the archive returned %d laps, want 6", len(laps))
+			}
+		})
+	}
+
+	if _, err := client.DownloadSession(context.Background(), 1, t.TempDir(), openf1go.DownloadOptions{RequestInterval: time.Millisecond, Chunk: time.Hour}); !errors.Is(err, openf1go.ErrSessionNotFound) {
+		t.Errorf("DownloadSession() for an unknown session returned %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestDownloadOptionsWithDefaults(t *testing.T) {
+	defaults := openf1go.DefaultDownloadOptions()
+
+	tests := []struct {
+		name string
+		opts openf1go.DownloadOptions
+		want openf1go.DownloadOptions
+		err  error
+	}{
+		{name: "zero value", opts: openf1go.DownloadOptions{}, want: defaults},
+		{name: "zero interval", opts: openf1go.DownloadOptions{Chunk: time.Hour}, want: openf1go.DownloadOptions{RequestInterval: defaults.RequestInterval, Chunk: time.Hour}},
+		{name: "zero chunk", opts: openf1go.DownloadOptions{RequestInterval: time.Second}, want: openf1go.DownloadOptions{RequestInterval: time.Second, Chunk: defaults.Chunk}},
+		{name: "set", opts: openf1go.DownloadOptions{RequestInterval: time.Second, Chunk: time.Hour}, want: openf1go.DownloadOptions{RequestInterval: time.Second, Chunk: time.Hour}},
+		{name: "negative interval", opts: openf1go.DownloadOptions{RequestInterval: -time.Second}, err: openf1go.ErrInvalidDownloadOptions},
+		{name: "negative chunk", opts: openf1go.DownloadOptions{Chunk: -time.Hour}, err: openf1go.ErrInvalidDownloadOptions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.WithDefaults()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("WithDefaults() returned %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("WithDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadSessionZeroOptions(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	// A zero chunk downloads in the default windows rather than looping forever
+	manifest, err := client.DownloadSession(ctx, 9158, t.TempDir(), openf1go.DownloadOptions{RequestInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("DownloadSession() with a zero chunk returned %v", err)
+	}
+	if got := manifest.Endpoints["car_data"].Records; got != 80 {
+		t.Errorf("car_data holds %d records, want 80", got)
+	}
+
+	if _, err := client.DownloadSession(ctx, 9158, t.TempDir(), openf1go.DownloadOptions{RequestInterval: -time.Second}); !errors.Is(err, openf1go.ErrInvalidDownloadOptions) {
+		t.Errorf("DownloadSession() with a negative interval returned %v, want ErrInvalidDownloadOptions", err)
+	}
+}
@@ -46,7 +46,7 @@ func (c *Client) GetCarData(CarData CarData) (CarDataResponse, error) {
 	}
 
 	// Make the HTTP GET request to fetch car data.
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +80,7 @@ func (c *Client) GetLatestCarDataByDriver(driver Driver) (CarDataResponse, error
 	}
 
 	// Make the HTTP GET request to fetch the latest car data.
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return CarDataResponse{}, err
 	}
@@ -92,3 +92,32 @@ func (c *Client) GetLatestCarDataByDriver(driver Driver) (CarDataResponse, error
 
 	return carDataResponse, nil
 }
+
+// GetCarDataBetween fetches car telemetry data matching the CarData filter with a date within [from, to).
+// Returns a CarDataResponse or an error if the request fails.
+func (c *Client) GetCarDataBetween(carData CarData, from, to time.Time) (CarDataResponse, error) {
+	var carDataResponse CarDataResponse
+
+	// Build query arguments for the filter and the date range.
+	args := buildArgs(carData)
+	args = append(args, Arg{Key: "date>=", Value: from.Format(time.RFC3339Nano)}, Arg{Key: "date<", Value: to.Format(time.RFC3339Nano)})
+
+	// Build the URL with the query arguments.
+	url, err := UrlBuilder(c.getCarDataURL(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make the HTTP GET request to fetch car data.
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response into the CarDataResponse structure.
+	if err := json.Unmarshal(resp, &carDataResponse); err != nil {
+		return nil, err
+	}
+
+	return carDataResponse, nil
+}
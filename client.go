@@ -4,23 +4,72 @@ package openf1go
 
 import (
 	"net/http"
+	"strings"
 	"time"
 )
 
+// DefaultBaseURL is the base URL of the public OpenF1 API
+const DefaultBaseURL = "https://api.openf1.org/v1"
+
+// defaultTimeout is the HTTP client timeout used unless another HTTP client is provided
+const defaultTimeout = 15 * time.Second
+
+// defaultHTTPClient sends the requests of an HTTPDataSource without its own HTTP client
+var defaultHTTPClient = &http.Client{Timeout: defaultTimeout}
+
 // Client is a struct that wraps an HTTP client and a base URL for API requests.
 type Client struct {
 	client  http.Client // HTTP client used to make requests
 	baseUrl string      // Base URL for the API
+	source  DataSource  // Source answering queries instead of the HTTP client when set
+}
+
+// Option configures a Client created by New.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different OpenF1 compatible API, e.g. a local test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseUrl = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient makes the client send requests with the given HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = *httpClient
+	}
+}
+
+// WithTransport makes the client send requests through the given transport, e.g. a recording transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.client.Transport = transport
+	}
+}
+
+// WithDataSource makes the client answer queries from the given source, e.g. session archives on disk.
+func WithDataSource(source DataSource) Option {
+	return func(c *Client) {
+		c.source = source
+	}
 }
 
 // New creates and returns a new instance of the Client struct.
 // It initializes the HTTP client with a timeout of 15 seconds and sets the base URL.
-func New() *Client {
-	c := http.Client{Timeout: time.Duration(15) * time.Second} // Set HTTP client timeout
-	return &Client{
+func New(opts ...Option) *Client {
+	c := http.Client{Timeout: defaultTimeout} // Set HTTP client timeout
+	client := &Client{
 		client:  c,
-		baseUrl: "https://api.openf1.org/v1", // Base URL for the OpenF1 API
+		baseUrl: DefaultBaseURL, // Base URL for the OpenF1 API
+	}
+
+	// Apply the provided options on top of the defaults
+	for _, opt := range opts {
+		opt(client)
 	}
+
+	return client
 }
 
 // getLatestSessionArgs returns a slice of Arg structs representing
@@ -0,0 +1,67 @@
+// Command openf1-archive downloads every endpoint of a session into a local archive.
+//
+// Usage:
+//
+//	openf1-archive -session 9158 [-dir archive/9158] [-base-url https://api.openf1.org/v1] [-interval 400ms] [-chunk 30m]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func main() {
+	sessionKey := flag.Int("session", 0, "key of the session to download")
+	dir := flag.String("dir", "", "directory to write the archive to (default archive/<session>)")
+	baseURL := flag.String("base-url", openf1go.DefaultBaseURL, "base URL of the OpenF1 API")
+	opts := openf1go.DefaultDownloadOptions()
+	flag.DurationVar(&opts.RequestInterval, "interval", opts.RequestInterval, "minimum time between two requests")
+	flag.DurationVar(&opts.Chunk, "chunk", opts.Chunk, "length of the time windows car data and location are downloaded in")
+	flag.Parse()
+
+	if *sessionKey == 0 {
+		fmt.Fprintln(os.Stderr, "openf1-archive: -session is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *dir == "" {
+		*dir = filepath.Join("archive", strconv.Itoa(*sessionKey))
+	}
+
+	// Stop cleanly on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := openf1go.New(openf1go.WithBaseURL(*baseURL))
+
+	start := time.Now()
+	manifest, err := client.DownloadSession(ctx, *sessionKey, *dir, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openf1-archive:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s %d %s (session %d) downloaded to %s in %s\n",
+		manifest.Session.CountryName, manifest.Session.Year, manifest.Session.SessionName,
+		manifest.SessionKey, *dir, time.Since(start).Round(time.Second))
+
+	endpoints := make([]string, 0, len(manifest.Endpoints))
+	for endpoint := range manifest.Endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	for _, endpoint := range endpoints {
+		file := manifest.Endpoints[endpoint]
+		fmt.Printf("  %-14s %8d records  %s\n", endpoint, file.Records, file.SHA256[:12])
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+// Keeps the state of the latest session and exposes it as Prometheus metrics.
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// driverLabels are the labels of every per driver metric
+var driverLabels = []string{"driver_number", "name_acronym", "team"}
+
+var (
+	sessionInfoDesc = prometheus.NewDesc("openf1_session_info",
+		"Session being exported, always 1.", []string{"session_key", "session_name", "circuit", "country", "year"}, nil)
+	lastEventDesc = prometheus.NewDesc("openf1_last_event_timestamp_seconds",
+		"Date of the most recent record received, as a Unix timestamp.", nil, nil)
+
+	positionDesc = prometheus.NewDesc("openf1_driver_position",
+		"Current position of the driver.", driverLabels, nil)
+	gapToLeaderDesc = prometheus.NewDesc("openf1_gap_to_leader_seconds",
+		"Gap to the race leader, absent while the driver is lapped.", driverLabels, nil)
+	gapToLeaderLapsDesc = prometheus.NewDesc("openf1_gap_to_leader_laps",
+		"Number of laps the driver is behind the race leader.", driverLabels, nil)
+	intervalDesc = prometheus.NewDesc("openf1_interval_seconds",
+		"Interval to the car ahead, absent while the car ahead is a lap or more ahead.", driverLabels, nil)
+	lapNumberDesc = prometheus.NewDesc("openf1_lap_number",
+		"Lap the driver is currently on.", driverLabels, nil)
+	lastLapDesc = prometheus.NewDesc("openf1_last_lap_seconds",
+		"Duration of the last completed lap.", driverLabels, nil)
+	bestLapDesc = prometheus.NewDesc("openf1_best_lap_seconds",
+		"Duration of the best completed lap.", driverLabels, nil)
+	tyreAgeDesc = prometheus.NewDesc("openf1_tyre_age_laps",
+		"Age of the tyres currently fitted.", append(driverLabels, "compound"), nil)
+	pitStopsDesc = prometheus.NewDesc("openf1_pit_stops_total",
+		"Pit stops made by the driver.", driverLabels, nil)
+
+	speedDesc = prometheus.NewDesc("openf1_car_speed_kmh",
+		"Latest speed of the car.", driverLabels, nil)
+	rpmDesc = prometheus.NewDesc("openf1_car_rpm",
+		"Latest engine revolutions per minute.", driverLabels, nil)
+	gearDesc = prometheus.NewDesc("openf1_car_gear",
+		"Latest gear of the car.", driverLabels, nil)
+	throttleDesc = prometheus.NewDesc("openf1_car_throttle_percent",
+		"Latest throttle pressure.", driverLabels, nil)
+	brakeDesc = prometheus.NewDesc("openf1_car_brake_percent",
+		"Latest brake pressure.", driverLabels, nil)
+
+	airTemperatureDesc = prometheus.NewDesc("openf1_air_temperature_celsius",
+		"Air temperature at the track.", nil, nil)
+	trackTemperatureDesc = prometheus.NewDesc("openf1_track_temperature_celsius",
+		"Track surface temperature.", nil, nil)
+	humidityDesc = prometheus.NewDesc("openf1_humidity_percent",
+		"Relative humidity at the track.", nil, nil)
+	pressureDesc = prometheus.NewDesc("openf1_air_pressure_hpa",
+		"Air pressure at the track.", nil, nil)
+	windSpeedDesc = prometheus.NewDesc("openf1_wind_speed_mps",
+		"Wind speed at the track.", nil, nil)
+	windDirectionDesc = prometheus.NewDesc("openf1_wind_direction_degrees",
+		"Wind direction at the track.", nil, nil)
+	rainfallDesc = prometheus.NewDesc("openf1_rainfall",
+		"Whether it is raining at the track.", nil, nil)
+)
+
+// exporter polls the latest session and implements prometheus.Collector over its state
+type exporter struct {
+	client     *openf1go.Client
+	pollErrors prometheus.Counter // Failed polls of any endpoint
+
+	mu          sync.Mutex
+	session     openf1go.Session         // Latest session, zero until first fetched
+	dataSession int                      // Session the records below belong to, taken from the records themselves
+	live        *openf1go.LiveSession    // Timing tower state
+	cars        map[int]openf1go.CarData // Latest car data per driver number
+	carSince    time.Time                // Date of the latest car data received
+	weather     openf1go.Weather         // Latest weather reading
+	updated     time.Time                // Date of the most recent record received
+}
+
+// newExporter creates an exporter polling with the client and registers its metrics
+func newExporter(client *openf1go.Client, reg prometheus.Registerer) *exporter {
+	e := &exporter{
+		client: client,
+		pollErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "openf1_poll_errors_total",
+			Help: "Polls of the OpenF1 API that failed.",
+		}),
+		live: openf1go.NewLiveSession(),
+		cars: map[int]openf1go.CarData{},
+	}
+	reg.MustRegister(e, e.pollErrors)
+	return e
+}
+
+// run polls the latest session at the given interval until the context is done.
+// Car data is only polled when carData is set, as it is by far the largest feed.
+func (e *exporter) run(ctx context.Context, interval time.Duration, carData bool) {
+	events := e.client.Subscribe(ctx, interval)
+
+	// Session and car data are not part of the event stream and are polled alongside it
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			e.pollSession()
+			if carData {
+				e.pollCarData(ctx, interval)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for event := range events {
+		e.apply(event)
+	}
+}
+
+// apply updates the state with an event
+func (e *exporter) apply(event openf1go.Event) {
+	if event.Kind == openf1go.EventError {
+		e.pollErrors.Inc()
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.switchSession(sessionKeyOf(event.Data)) {
+		return
+	}
+
+	if weather, ok := event.Data.(openf1go.Weather); ok {
+		if !weather.Date.Before(e.weather.Date) {
+			e.weather = weather
+		}
+	} else {
+		e.live.Apply(event)
+	}
+
+	if event.Date.After(e.updated) {
+		e.updated = event.Date
+	}
+}
+
+// switchSession clears the state when a record of a newer session arrives, called with the lock held.
+// It reports whether a record of the session should be applied, records of an earlier session are not.
+func (e *exporter) switchSession(sessionKey int) bool {
+	switch {
+	case sessionKey == 0 || sessionKey == e.dataSession:
+		return true
+	case sessionKey < e.dataSession:
+		return false
+	}
+
+	if e.dataSession != 0 {
+		e.live = openf1go.NewLiveSession()
+		e.cars = map[int]openf1go.CarData{}
+		e.carSince = time.Time{}
+		e.weather = openf1go.Weather{}
+		e.updated = time.Time{}
+	}
+	e.dataSession = sessionKey
+	return true
+}
+
+// sessionKeyOf returns the session key of a record, zero when it has none
+func sessionKeyOf(record any) int {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+	if f := v.FieldByName("SessionKey"); f.IsValid() && f.CanInt() {
+		return int(f.Int())
+	}
+	return 0
+}
+
+// pollSession fetches the latest session for the session info metric.
+// The state is cleared by the records of a new session rather than here, as they may arrive before or after it.
+func (e *exporter) pollSession() {
+	session, err := e.client.GetLatestSessions()
+	if err != nil {
+		e.pollErrors.Inc()
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.session = session
+}
+
+// pollCarData fetches the car data received since the previous poll and keeps the latest sample per driver
+func (e *exporter) pollCarData(ctx context.Context, interval time.Duration) {
+	e.mu.Lock()
+	since := e.carSince
+	if since.IsZero() && !e.updated.IsZero() {
+		// Start from the latest record of the other feeds rather than fetching the whole session
+		since = e.updated.Add(-interval)
+	}
+	e.mu.Unlock()
+
+	if since.IsZero() {
+		return
+	}
+
+	body, err := e.client.Raw(ctx, "car_data", []openf1go.Arg{
+		{Key: "session_key", Value: "latest"},
+		{Key: "date>", Value: since.UTC().Format(time.RFC3339Nano)},
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			e.pollErrors.Inc()
+		}
+		return
+	}
+
+	var samples openf1go.CarDataResponse
+	if err := json.Unmarshal(body, &samples); err != nil {
+		e.pollErrors.Inc()
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, sample := range samples {
+		if !e.switchSession(sample.SessionKey) {
+			continue
+		}
+		if !sample.Date.Before(e.cars[sample.DriverNumber].Date) {
+			e.cars[sample.DriverNumber] = sample
+		}
+		if sample.Date.After(e.carSince) {
+			e.carSince = sample.Date
+		}
+	}
+}
+
+// Describe sends the descriptors of every metric the exporter collects
+func (e *exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		sessionInfoDesc, lastEventDesc,
+		positionDesc, gapToLeaderDesc, gapToLeaderLapsDesc, intervalDesc, lapNumberDesc, lastLapDesc, bestLapDesc, tyreAgeDesc, pitStopsDesc,
+		speedDesc, rpmDesc, gearDesc, throttleDesc, brakeDesc,
+		airTemperatureDesc, trackTemperatureDesc, humidityDesc, pressureDesc, windSpeedDesc, windDirectionDesc, rainfallDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect sends the current value of every metric
+func (e *exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// gauge sends a gauge metric
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	}
+
+	if e.session.SessionKey != 0 {
+		gauge(sessionInfoDesc, 1, strconv.Itoa(e.session.SessionKey), e.session.SessionName,
+			e.session.CircuitShortName, e.session.CountryName, strconv.Itoa(e.session.Year))
+	}
+	if !e.updated.IsZero() {
+		gauge(lastEventDesc, float64(e.updated.UnixMilli())/1000)
+	}
+
+	// Timing tower
+	for _, row := range e.live.Snapshot() {
+		labels := []string{strconv.Itoa(row.DriverNumber), row.NameAcronym, row.TeamName}
+
+		if row.Position > 0 {
+			gauge(positionDesc, float64(row.Position), labels...)
+		}
+		switch {
+		case row.Position == 1:
+			gauge(gapToLeaderDesc, 0, labels...)
+		case row.GapToLeader.Valid && row.GapToLeader.Laps > 0:
+			gauge(gapToLeaderLapsDesc, float64(row.GapToLeader.Laps), labels...)
+		case row.GapToLeader.Valid:
+			gauge(gapToLeaderDesc, row.GapToLeader.Seconds, labels...)
+		}
+		if row.Interval.Valid && row.Interval.Laps == 0 {
+			gauge(intervalDesc, row.Interval.Seconds, labels...)
+		}
+		if row.LapNumber > 0 {
+			gauge(lapNumberDesc, float64(row.LapNumber), labels...)
+		}
+		if row.LastLap > 0 {
+			gauge(lastLapDesc, row.LastLap, labels...)
+		}
+		if row.BestLap > 0 {
+			gauge(bestLapDesc, row.BestLap, labels...)
+		}
+		if row.Compound != "" {
+			gauge(tyreAgeDesc, float64(row.TyreAge), append(labels, row.Compound)...)
+		}
+		ch <- prometheus.MustNewConstMetric(pitStopsDesc, prometheus.CounterValue, float64(row.PitCount), labels...)
+
+		// Car data of drivers on the timing tower
+		if car, ok := e.cars[row.DriverNumber]; ok {
+			gauge(speedDesc, float64(car.Speed), labels...)
+			gauge(rpmDesc, float64(car.Rpm), labels...)
+			gauge(gearDesc, float64(car.NGear), labels...)
+			gauge(throttleDesc, float64(car.Throttle), labels...)
+			gauge(brakeDesc, float64(car.Brake), labels...)
+		}
+	}
+
+	// Weather
+	if !e.weather.Date.IsZero() {
+		gauge(airTemperatureDesc, e.weather.AirTemperature)
+		gauge(trackTemperatureDesc, e.weather.TrackTemperature)
+		gauge(humidityDesc, e.weather.Humidity)
+		gauge(pressureDesc, e.weather.Pressure)
+		gauge(windSpeedDesc, e.weather.WindSpeed)
+		gauge(windDirectionDesc, float64(e.weather.WindDirection))
+		gauge(rainfallDesc, float64(e.weather.Rainfall))
+	}
+}
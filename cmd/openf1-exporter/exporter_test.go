@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func TestExporterApplySessionChange(t *testing.T) {
+	start := time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+	next := start.Add(3 * time.Hour)
+
+	tests := []struct {
+		name    string
+		records []any
+		drivers []int     // Drivers on the timing tower afterwards
+		updated time.Time // Date of the most recent record afterwards
+		weather float64   // Air temperature afterwards
+	}{
+		{
+			name: "one session",
+			records: []any{
+				openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start},
+				openf1go.Weather{SessionKey: 9158, AirTemperature: 30, Date: start.Add(time.Minute)},
+			},
+			drivers: []int{1},
+			updated: start.Add(time.Minute),
+			weather: 30,
+		},
+		{
+			name: "new session clears the state",
+			records: []any{
+				openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start},
+				openf1go.Weather{SessionKey: 9158, AirTemperature: 30, Date: start.Add(time.Minute)},
+				openf1go.Position{SessionKey: 9159, DriverNumber: 44, Position: 1, Date: next},
+			},
+			drivers: []int{44},
+			updated: next,
+		},
+		{
+			name: "records of the previous session are dropped",
+			records: []any{
+				openf1go.Position{SessionKey: 9159, DriverNumber: 44, Position: 1, Date: next},
+				openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: next.Add(time.Minute)},
+				openf1go.Weather{SessionKey: 9158, AirTemperature: 30, Date: next.Add(time.Minute)},
+			},
+			drivers: []int{44},
+			updated: next,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newExporter(openf1go.New(), prometheus.NewRegistry())
+			for _, record := range tt.records {
+				e.apply(openf1go.NewEvent(record))
+			}
+
+			drivers := []int{}
+			for _, row := range e.live.Snapshot() {
+				drivers = append(drivers, row.DriverNumber)
+			}
+			if len(drivers) != len(tt.drivers) || (len(drivers) > 0 && drivers[0] != tt.drivers[0]) {
+				t.Errorf("timing tower drivers = %v, want %v", drivers, tt.drivers)
+			}
+			if !e.updated.Equal(tt.updated) {
+				t.Errorf("updated = %v, want %v", e.updated, tt.updated)
+			}
+			if e.weather.AirTemperature != tt.weather {
+				t.Errorf("air temperature = %v, want %v", e.weather.AirTemperature, tt.weather)
+			}
+		})
+	}
+}
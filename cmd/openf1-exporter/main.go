@@ -0,0 +1,63 @@
+// Command openf1-exporter polls the latest session and exposes the timing, car data and weather
+// as Prometheus metrics, along with metrics about the requests sent to the API.
+//
+// Usage:
+//
+//	openf1-exporter [-listen :9101] [-interval 4s] [-car-data=false] [-base-url https://api.openf1.org/v1]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func main() {
+	listen := flag.String("listen", ":9101", "address to serve the metrics on")
+	interval := flag.Duration("interval", 4*time.Second, "polling interval")
+	carData := flag.Bool("car-data", true, "poll car data for the speed, rpm, gear, throttle and brake metrics")
+	baseURL := flag.String("base-url", openf1go.DefaultBaseURL, "base URL of the OpenF1 API")
+	flag.Parse()
+
+	// Stop cleanly on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	// Instrument every request the client sends
+	client := openf1go.New(
+		openf1go.WithBaseURL(*baseURL),
+		openf1go.WithTransport(newTransport(http.DefaultTransport, reg)),
+	)
+	e := newExporter(client, reg)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go e.run(ctx, *interval, *carData)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(os.Stderr, "openf1-exporter: serving metrics on %s/metrics\n", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(os.Stderr, "openf1-exporter:", err)
+		os.Exit(1)
+	}
+}
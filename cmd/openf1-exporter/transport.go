@@ -0,0 +1,76 @@
+package main
+
+// Instruments the requests the client sends to the API.
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// transport wraps the transport of the client and records the requests it sends
+type transport struct {
+	next     http.RoundTripper        // Transport sending the requests
+	requests *prometheus.CounterVec   // Requests by endpoint and status code
+	errors   *prometheus.CounterVec   // Failed requests by endpoint
+	duration *prometheus.HistogramVec // Request latency by endpoint
+	inFlight prometheus.Gauge         // Requests waiting for a response
+}
+
+// newTransport creates an instrumented transport and registers its metrics
+func newTransport(next http.RoundTripper, reg prometheus.Registerer) *transport {
+	t := &transport{
+		next: next,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "openf1_client_requests_total",
+			Help: "Requests sent to the OpenF1 API by endpoint and HTTP status code.",
+		}, []string{"endpoint", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "openf1_client_request_errors_total",
+			Help: "Requests to the OpenF1 API that failed or returned a non 2xx status, by endpoint.",
+		}, []string{"endpoint"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "openf1_client_request_duration_seconds",
+			Help:    "Latency of requests to the OpenF1 API by endpoint.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"endpoint"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "openf1_client_requests_in_flight",
+			Help: "Requests to the OpenF1 API waiting for a response.",
+		}),
+	}
+	reg.MustRegister(t.requests, t.errors, t.duration, t.inFlight)
+	return t
+}
+
+// RoundTrip sends the request with the wrapped transport and records its outcome
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The endpoint is the last path segment, e.g. "car_data"
+	endpoint := path.Base(req.URL.Path)
+
+	t.inFlight.Inc()
+	defer t.inFlight.Dec()
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	t.duration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		// Requests cancelled on shutdown are not failures of the API
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		t.requests.WithLabelValues(endpoint, "error").Inc()
+		t.errors.WithLabelValues(endpoint).Inc()
+		return nil, err
+	}
+
+	t.requests.WithLabelValues(endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		t.errors.WithLabelValues(endpoint).Inc()
+	}
+	return resp, nil
+}
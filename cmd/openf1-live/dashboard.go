@@ -0,0 +1,283 @@
+package main
+
+// Keeps the state shown on the dashboard and renders it with ANSI escape sequences.
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// raceControlLines is the number of race control messages shown
+const raceControlLines = 8
+
+const (
+	reset = "\x1b[0m"
+	bold  = "\x1b[1m"
+	dim   = "\x1b[90m"
+)
+
+// compoundColors maps tyre compounds to their letter and ANSI color
+var compoundColors = map[string]string{
+	"SOFT":         "\x1b[31mS",
+	"MEDIUM":       "\x1b[33mM",
+	"HARD":         "\x1b[97mH",
+	"INTERMEDIATE": "\x1b[32mI",
+	"WET":          "\x1b[34mW",
+}
+
+// dashboard holds everything shown on screen and is safe for concurrent use
+type dashboard struct {
+	mu          sync.Mutex
+	title       string                // Session shown in the header
+	mode        string                // "LIVE" or the replay speed
+	sessionKey  int                   // Session the state below belongs to, taken from the records once they arrive
+	live        *openf1go.LiveSession // Timing tower state
+	laps        map[int]openf1go.Lap  // Latest lap with mini-sector data per driver
+	raceControl []openf1go.RaceControl
+	weather     openf1go.Weather
+	clock       time.Time // Date of the latest event
+	err         error     // Latest polling error, cleared by the next successful event
+	dirty       bool      // Indicates the screen needs redrawing
+}
+
+// newDashboard creates an empty dashboard for a session
+func newDashboard(session openf1go.Session, mode string) *dashboard {
+	return &dashboard{
+		title:      sessionTitle(session),
+		mode:       mode,
+		sessionKey: session.SessionKey,
+		live:       openf1go.NewLiveSession(),
+		laps:       map[int]openf1go.Lap{},
+		dirty:      true,
+	}
+}
+
+// sessionTitle returns the header text of a session
+func sessionTitle(session openf1go.Session) string {
+	return fmt.Sprintf("%s %d – %s", session.CountryName, session.Year, session.SessionName)
+}
+
+// setSession shows a session in the header
+func (d *dashboard) setSession(session openf1go.Session) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.title = sessionTitle(session)
+	d.dirty = true
+}
+
+// apply updates the dashboard with an event.
+// It reports whether the event belongs to a newer session, in which case the state of the previous one was cleared.
+func (d *dashboard) apply(event openf1go.Event) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	apply, switched := d.switchSession(sessionKeyOf(event.Data))
+	if !apply {
+		return false
+	}
+
+	d.live.Apply(event)
+	d.dirty = true
+	if event.Date.After(d.clock) {
+		d.clock = event.Date
+	}
+
+	switch data := event.Data.(type) {
+	case openf1go.Lap:
+		// Keep the most recent lap that has mini-sector data
+		if previous, ok := d.laps[data.DriverNumber]; !ok || data.LapNumber >= previous.LapNumber {
+			if len(data.SegmentsSector1)+len(data.SegmentsSector2)+len(data.SegmentsSector3) > 0 {
+				d.laps[data.DriverNumber] = data
+			}
+		}
+	case openf1go.RaceControl:
+		d.raceControl = append(d.raceControl, data)
+		if len(d.raceControl) > raceControlLines {
+			d.raceControl = d.raceControl[len(d.raceControl)-raceControlLines:]
+		}
+	case openf1go.Weather:
+		if !data.Date.Before(d.weather.Date) {
+			d.weather = data
+		}
+	case error:
+		d.err = data
+		return switched
+	}
+	d.err = nil
+	return switched
+}
+
+// switchSession clears the state when a record of a newer session arrives, called with the lock held.
+// It reports whether a record of the session should be applied, records of an earlier session are not,
+// and whether the state was cleared.
+func (d *dashboard) switchSession(sessionKey int) (apply, switched bool) {
+	switch {
+	case sessionKey == 0 || sessionKey == d.sessionKey:
+		return true, false
+	case sessionKey < d.sessionKey:
+		return false, false
+	}
+
+	d.sessionKey = sessionKey
+	d.live = openf1go.NewLiveSession()
+	d.laps = map[int]openf1go.Lap{}
+	d.raceControl = nil
+	d.weather = openf1go.Weather{}
+	d.clock = time.Time{}
+	return true, true
+}
+
+// sessionKeyOf returns the session key of a record, zero when it has none
+func sessionKeyOf(record any) int {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+	if f := v.FieldByName("SessionKey"); f.IsValid() && f.CanInt() {
+		return int(f.Int())
+	}
+	return 0
+}
+
+// setClock sets the time shown in the header, used by replays to show the virtual clock
+func (d *dashboard) setClock(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !t.Equal(d.clock) {
+		d.clock = t
+		d.dirty = true
+	}
+}
+
+// render draws the dashboard when it changed since the last call
+func (d *dashboard) render(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.dirty {
+		return
+	}
+	d.dirty = false
+	tower := d.live.Snapshot()
+
+	var b strings.Builder
+
+	// Move the cursor home and clear the screen
+	b.WriteString("\x1b[H\x1b[2J")
+
+	leaderLap := 0
+	for _, row := range tower {
+		leaderLap = max(leaderLap, row.LapNumber)
+	}
+	fmt.Fprintf(&b, "%s OPENF1 %s %s  %s   Lap %d   %s UTC\n\n", bold, d.mode, reset, d.title, leaderLap, d.clock.UTC().Format(time.TimeOnly))
+
+	// Timing tower
+	fmt.Fprintf(&b, "%s POS  DRIVER     GAP         INTERVAL    LAST       BEST       MINI-SECTORS               TYRE  AGE  PIT%s\n", dim, reset)
+	for _, row := range tower {
+		position := "  -"
+		if row.Position > 0 {
+			position = fmt.Sprintf("%3d", row.Position)
+		}
+
+		gap := row.GapToLeader.String()
+		if row.Position == 1 {
+			gap = "LEADER"
+		}
+
+		sectors := ""
+		if lap, ok := d.laps[row.DriverNumber]; ok {
+			sectors = lap.MiniSectors().ANSI()
+		}
+
+		fmt.Fprintf(&b, " %s  %s%3d %-4s%s  %-10s  %-10s  %-9s  %-9s  %s  %s%s  %3d  %3d\n",
+			position,
+			teamColor(row.TeamColour), row.DriverNumber, row.NameAcronym, reset,
+			gap, row.Interval.String(),
+			formatLapTime(row.LastLap), formatLapTime(row.BestLap),
+			pad(sectors, 25),
+			compound(row.Compound), reset, row.TyreAge, row.PitCount)
+	}
+
+	// Race control feed, newest first
+	fmt.Fprintf(&b, "\n%s RACE CONTROL%s\n", dim, reset)
+	for i := len(d.raceControl) - 1; i >= 0; i-- {
+		rc := d.raceControl[i]
+		fmt.Fprintf(&b, " %s  L%-3d %s\n", rc.Date.UTC().Format(time.TimeOnly), rc.LapNumber, rc.Message)
+	}
+
+	// Weather panel
+	fmt.Fprintf(&b, "\n%s WEATHER%s\n", dim, reset)
+	if !d.weather.Date.IsZero() {
+		rain := "no"
+		if d.weather.Rainfall > 0 {
+			rain = "yes"
+		}
+		fmt.Fprintf(&b, " Air %.1f°C   Track %.1f°C   Humidity %.0f%%   Wind %.1f m/s %d°   Rain %s\n",
+			d.weather.AirTemperature, d.weather.TrackTemperature, d.weather.Humidity,
+			d.weather.WindSpeed, d.weather.WindDirection, rain)
+	}
+
+	if d.err != nil {
+		fmt.Fprintf(&b, "\n\x1b[31m %v%s\n", d.err, reset)
+	}
+
+	io.WriteString(w, b.String())
+}
+
+// formatLapTime formats a duration in seconds as m:ss.sss, empty when unknown
+func formatLapTime(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	minutes := int(seconds) / 60
+	return fmt.Sprintf("%d:%06.3f", minutes, seconds-float64(minutes*60))
+}
+
+// teamColor returns the ANSI escape sequence for a team colour such as "3671C6"
+func teamColor(hex string) string {
+	if len(hex) != 6 {
+		return ""
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\x1b[1;38;2;%d;%d;%dm", rgb>>16, rgb>>8&0xff, rgb&0xff)
+}
+
+// compound returns the colored letter of a tyre compound
+func compound(name string) string {
+	if c, ok := compoundColors[name]; ok {
+		return "  " + c
+	}
+	return "   "
+}
+
+// pad pads a string containing escape sequences to a visible width
+func pad(s string, width int) string {
+	visible := 0
+	escaped := false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escaped = true
+		case escaped:
+			escaped = r != 'm'
+		default:
+			visible++
+		}
+	}
+	if visible >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-visible)
+}
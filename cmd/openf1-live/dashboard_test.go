@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+var start = time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// newTestDashboard creates a dashboard of session 9158 with the given records applied
+func newTestDashboard(records ...any) *dashboard {
+	d := newDashboard(openf1go.Session{SessionKey: 9158, CountryName: "Singapore", Year: 2023, SessionName: "Race"}, "LIVE")
+	for _, record := range records {
+		d.apply(openf1go.NewEvent(record))
+	}
+	return d
+}
+
+func TestDashboardApply(t *testing.T) {
+	segments := []int{2049, 2051}
+
+	records := []any{
+		openf1go.Driver{SessionKey: 9158, DriverNumber: 1, NameAcronym: "VER", TeamColour: "3671C6"},
+		openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start},
+		openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 3, DateStart: start.Add(time.Minute), SegmentsSector1: segments},
+		openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 2, DateStart: start, SegmentsSector1: []int{2048}}, // Older lap
+		openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 4, DateStart: start.Add(2 * time.Minute)},          // No mini-sectors yet
+		openf1go.Weather{SessionKey: 9158, AirTemperature: 30, Date: start.Add(time.Minute)},
+		openf1go.Weather{SessionKey: 9158, AirTemperature: 25, Date: start}, // Older reading
+	}
+	for i := 0; i < raceControlLines+2; i++ {
+		records = append(records, openf1go.RaceControl{SessionKey: 9158, Message: "message", LapNumber: i, Date: start.Add(time.Duration(i) * time.Second)})
+	}
+	d := newTestDashboard(records...)
+
+	if lap := d.laps[1]; lap.LapNumber != 3 {
+		t.Errorf("mini-sectors of lap %d shown, want lap 3", lap.LapNumber)
+	}
+	if d.weather.AirTemperature != 30 {
+		t.Errorf("air temperature = %v, want 30", d.weather.AirTemperature)
+	}
+	if len(d.raceControl) != raceControlLines || d.raceControl[0].LapNumber != 2 {
+		t.Errorf("race control holds %d messages from lap %d, want %d from lap 2", len(d.raceControl), d.raceControl[0].LapNumber, raceControlLines)
+	}
+	if !d.clock.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("clock = %v, want the date of the latest record", d.clock)
+	}
+
+	// An error is shown until the next record arrives
+	d.apply(openf1go.Event{Kind: openf1go.EventError, Data: errors.New("unavailable")})
+	if d.err == nil {
+		t.Error("error not shown")
+	}
+	d.apply(openf1go.NewEvent(openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start.Add(3 * time.Minute)}))
+	if d.err != nil {
+		t.Errorf("error %v still shown after a record", d.err)
+	}
+}
+
+func TestDashboardSessionChange(t *testing.T) {
+	next := start.Add(3 * time.Hour)
+
+	tests := []struct {
+		name     string
+		records  []any
+		switched bool  // Whether the last record switched sessions
+		drivers  []int // Drivers on the timing tower afterwards
+		laps     int   // Laps with mini-sectors afterwards
+		messages int   // Race control messages afterwards
+	}{
+		{
+			name: "one session",
+			records: []any{
+				openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start},
+				openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 2, SegmentsSector1: []int{2048}},
+				openf1go.RaceControl{SessionKey: 9158, Message: "GREEN LIGHT", Date: start},
+			},
+			drivers:  []int{1},
+			laps:     1,
+			messages: 1,
+		},
+		{
+			name: "new session clears the state",
+			records: []any{
+				openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start},
+				openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 2, SegmentsSector1: []int{2048}},
+				openf1go.RaceControl{SessionKey: 9158, Message: "CHEQUERED FLAG", Date: start},
+				openf1go.Position{SessionKey: 9159, DriverNumber: 44, Position: 1, Date: next},
+			},
+			switched: true,
+			drivers:  []int{44},
+		},
+		{
+			name: "records of the previous session are dropped",
+			records: []any{
+				openf1go.Position{SessionKey: 9159, DriverNumber: 44, Position: 1, Date: next},
+				openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: next.Add(time.Minute)},
+				openf1go.RaceControl{SessionKey: 9158, Message: "CHEQUERED FLAG", Date: next.Add(time.Minute)},
+			},
+			drivers: []int{44},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDashboard(tt.records[:len(tt.records)-1]...)
+			if switched := d.apply(openf1go.NewEvent(tt.records[len(tt.records)-1])); switched != tt.switched {
+				t.Errorf("apply() = %v, want %v", switched, tt.switched)
+			}
+
+			drivers := []int{}
+			for _, row := range d.live.Snapshot() {
+				drivers = append(drivers, row.DriverNumber)
+			}
+			if len(drivers) != len(tt.drivers) || drivers[0] != tt.drivers[0] {
+				t.Errorf("timing tower drivers = %v, want %v", drivers, tt.drivers)
+			}
+			if len(d.laps) != tt.laps || len(d.raceControl) != tt.messages {
+				t.Errorf("dashboard holds %d laps and %d messages, want %d and %d", len(d.laps), len(d.raceControl), tt.laps, tt.messages)
+			}
+		})
+	}
+}
+
+func TestDashboardRender(t *testing.T) {
+	d := newTestDashboard(
+		openf1go.Driver{SessionKey: 9158, DriverNumber: 1, NameAcronym: "VER"},
+		openf1go.Driver{SessionKey: 9158, DriverNumber: 44, NameAcronym: "HAM"},
+		openf1go.Position{SessionKey: 9158, DriverNumber: 1, Position: 1, Date: start},
+		openf1go.Position{SessionKey: 9158, DriverNumber: 44, Position: 2, Date: start},
+		openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 12, DateStart: start, LapDuration: 98.424},
+		openf1go.RaceControl{SessionKey: 9158, Message: "DRS ENABLED", LapNumber: 3, Date: start},
+		openf1go.Weather{SessionKey: 9158, AirTemperature: 30.2, Rainfall: 1, Date: start},
+	)
+
+	var b strings.Builder
+	d.render(&b)
+	screen := b.String()
+	for _, want := range []string{"Singapore 2023 – Race", "Lap 12", "12:00:00 UTC", "VER", "LEADER", "HAM", "DRS ENABLED", "Air 30.2°C", "Rain yes"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("render() output misses %q:\n%s", want, screen)
+		}
+	}
+
+	// Nothing is drawn until the dashboard changes
+	b.Reset()
+	d.render(&b)
+	if b.Len() != 0 {
+		t.Errorf("render() of an unchanged dashboard wrote %q", b.String())
+	}
+
+	d.setSession(openf1go.Session{CountryName: "Japan", Year: 2023, SessionName: "Race"})
+	d.render(&b)
+	if !strings.Contains(b.String(), "Japan 2023 – Race") {
+		t.Errorf("render() after setSession() shows %q", b.String())
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "lap time", got: formatLapTime(98.424), want: "1:38.424"},
+		{name: "lap time under a minute", got: formatLapTime(59.5), want: "0:59.500"},
+		{name: "unknown lap time", got: formatLapTime(0), want: ""},
+		{name: "team color", got: teamColor("3671C6"), want: "\x1b[1;38;2;54;113;198m"},
+		{name: "invalid team color", got: teamColor("zzzzzz"), want: ""},
+		{name: "short team color", got: teamColor("fff"), want: ""},
+		{name: "compound", got: compound("SOFT"), want: "  \x1b[31mS"},
+		{name: "unknown compound", got: compound(""), want: "   "},
+		{name: "pad escape sequences", got: pad("\x1b[32m■\x1b[0m", 3), want: "\x1b[32m■\x1b[0m  "},
+		{name: "pad wide text", got: pad("abcd", 3), want: "abcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Command openf1-live shows a live timing dashboard in the terminal: the timing tower with mini-sectors,
+// tyres and pit stops, the race control feed and the weather.
+//
+// Usage:
+//
+//	openf1-live [-interval 4s]                     follow the latest session live
+//	openf1-live -replay 9158 [-speed 10] [-lap 20]  replay a historical session
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/replay"
+)
+
+func main() {
+	replayKey := flag.Int("replay", 0, "key of a historical session to replay instead of following the latest session")
+	speed := flag.Float64("speed", 1, "replay speed, e.g. 10 for ten times real time")
+	lap := flag.Int("lap", 0, "lap to start the replay at")
+	interval := flag.Duration("interval", 4*time.Second, "polling interval when following the latest session")
+	baseURL := flag.String("base-url", openf1go.DefaultBaseURL, "base URL of the OpenF1 API")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, openf1go.New(openf1go.WithBaseURL(*baseURL)), *replayKey, *speed, *lap, *interval); err != nil {
+		fmt.Fprintln(os.Stderr, "openf1-live:", err)
+		os.Exit(1)
+	}
+}
+
+// run feeds the dashboard from the latest session or a replay and redraws it until the context is done
+func run(ctx context.Context, client *openf1go.Client, replayKey int, speed float64, lap int, interval time.Duration) error {
+	var (
+		d       *dashboard
+		events  <-chan openf1go.Event
+		player  *replay.Replayer
+		skipped []openf1go.Event // Events before the lap the replay starts at
+	)
+
+	if replayKey != 0 {
+		sessions, err := client.GetSessions(openf1go.Session{SessionKey: replayKey})
+		if err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			return openf1go.ErrSessionNotFound
+		}
+
+		fmt.Fprintln(os.Stderr, "Loading session", replayKey, "...")
+		player, err = replay.Load(client, sessions[0], replay.LoadOptions{SkipTelemetry: true})
+		if err != nil {
+			return err
+		}
+
+		// Start at the requested lap, using the timeline to find when it started
+		if lap > 1 {
+			timeline, err := client.GetSessionTimeline(sessions[0])
+			if err != nil {
+				return err
+			}
+			if start, ok := timeline.LapEnd(lap - 1); ok {
+				skipped = player.FastForward(start)
+			}
+		}
+
+		d = newDashboard(sessions[0], fmt.Sprintf("REPLAY %gx", speed))
+		for _, event := range skipped {
+			d.apply(event)
+		}
+		player.SetSpeed(speed)
+		player.Play()
+		events = player.Run(ctx)
+	} else {
+		session, err := client.GetLatestSessions()
+		if err != nil {
+			return err
+		}
+		d = newDashboard(session, "LIVE")
+		events = client.Subscribe(ctx, interval)
+	}
+
+	// Draw on the alternate screen with the cursor hidden, restoring the terminal on exit
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// A finished replay stays on screen until interrupted
+				events = nil
+				continue
+			}
+			// Show the new session once the latest session changes
+			if d.apply(event) && player == nil {
+				if session, err := client.GetLatestSessions(); err == nil {
+					d.setSession(session)
+				}
+			}
+		case <-ticker.C:
+			if player != nil {
+				d.setClock(player.Now())
+			}
+			d.render(os.Stdout)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
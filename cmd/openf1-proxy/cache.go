@@ -0,0 +1,181 @@
+package main
+
+// Caches upstream responses in memory or in a SQLite database shared across restarts.
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stephenhoran/open-f1-go/storage"
+)
+
+// timeLayout is the layout dates are stored with: UTC with fixed precision so dates compare as text
+const timeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// entry represents a cached response
+type entry struct {
+	body    []byte    // Response body as returned by the API
+	fetched time.Time // Time the response was fetched
+	expires time.Time // Time the response goes stale, zero when it never does
+}
+
+// fresh reports whether the entry can be served without asking the API
+func (e entry) fresh(now time.Time) bool {
+	return e.expires.IsZero() || now.Before(e.expires)
+}
+
+// cache stores responses by endpoint and canonical query
+type cache interface {
+	get(ctx context.Context, key string) (entry, bool, error)
+	put(ctx context.Context, key string, e entry) error
+	prune(ctx context.Context, before time.Time) error // Removes entries that went stale before the given time
+	Close() error
+}
+
+// memoryCache is a cache held in memory, lost on restart.
+// It holds at most maxEntries responses, evicting the least recently used first.
+type memoryCache struct {
+	maxEntries int // Maximum number of responses held
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // Elements of order by key
+	order   *list.List               // Cached responses as *memoryEntry, most recently used first
+}
+
+// memoryEntry is a response held by a memory cache
+type memoryEntry struct {
+	key   string
+	entry entry
+}
+
+// newMemoryCache creates an empty memory cache holding at most maxEntries responses
+func newMemoryCache(maxEntries int) *memoryCache {
+	return &memoryCache{maxEntries: maxEntries, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+func (c *memoryCache) get(_ context.Context, key string) (entry, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return entry{}, false, nil
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*memoryEntry).entry, true, nil
+}
+
+func (c *memoryCache) put(_ context.Context, key string, e entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*memoryEntry).entry = e
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, entry: e})
+
+	// Evict the least recently used responses
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+func (c *memoryCache) prune(_ context.Context, before time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if e := el.Value.(*memoryEntry).entry; !e.expires.IsZero() && e.expires.Before(before) {
+			c.order.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+func (c *memoryCache) Close() error {
+	return nil
+}
+
+// sqliteCache is a cache kept in a table of a storage database
+type sqliteCache struct {
+	store *storage.Store
+}
+
+// openSQLiteCache opens the database at path and creates the cache table when needed
+func openSQLiteCache(path string) (*sqliteCache, error) {
+	store, err := storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = store.DB().Exec(`CREATE TABLE IF NOT EXISTS proxy_responses (
+		key TEXT PRIMARY KEY,
+		body BLOB NOT NULL,
+		fetched TEXT NOT NULL,
+		expires TEXT
+	)`)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+
+	return &sqliteCache{store: store}, nil
+}
+
+func (c *sqliteCache) get(ctx context.Context, key string) (entry, bool, error) {
+	var (
+		e       entry
+		fetched string
+		expires sql.NullString
+	)
+
+	err := c.store.DB().QueryRowContext(ctx, `SELECT body, fetched, expires FROM proxy_responses WHERE key = ?`, key).Scan(&e.body, &fetched, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entry{}, false, nil
+	}
+	if err != nil {
+		return entry{}, false, err
+	}
+
+	if e.fetched, err = time.Parse(timeLayout, fetched); err != nil {
+		return entry{}, false, err
+	}
+	if expires.Valid {
+		if e.expires, err = time.Parse(timeLayout, expires.String); err != nil {
+			return entry{}, false, err
+		}
+	}
+
+	return e, true, nil
+}
+
+func (c *sqliteCache) put(ctx context.Context, key string, e entry) error {
+	// Entries that never go stale are stored without an expiry
+	var expires sql.NullString
+	if !e.expires.IsZero() {
+		expires = sql.NullString{String: e.expires.UTC().Format(timeLayout), Valid: true}
+	}
+
+	_, err := c.store.DB().ExecContext(ctx, `INSERT OR REPLACE INTO proxy_responses (key, body, fetched, expires) VALUES (?, ?, ?, ?)`,
+		key, e.body, e.fetched.UTC().Format(timeLayout), expires)
+	return err
+}
+
+func (c *sqliteCache) prune(ctx context.Context, before time.Time) error {
+	_, err := c.store.DB().ExecContext(ctx, `DELETE FROM proxy_responses WHERE expires IS NOT NULL AND expires < ?`, before.UTC().Format(timeLayout))
+	return err
+}
+
+func (c *sqliteCache) Close() error {
+	return c.store.Close()
+}
@@ -0,0 +1,70 @@
+// Command openf1-proxy mirrors the OpenF1 API for services sharing its rate limit.
+// Responses are cached by endpoint and query, identical requests in flight are coalesced into one,
+// and only misses are forwarded to the API, spaced out to stay below its rate limit.
+// Responses pinned to sessions that ended, or to dates long past, are cached for good.
+//
+// Usage:
+//
+//	openf1-proxy [-listen :8080] [-db proxy.db] [-cache-entries 10000] [-ttl 3s] [-schedule-ttl 1h] [-interval 400ms] [-upstream https://api.openf1.org/v1]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to serve the API on")
+	db := flag.String("db", "", "SQLite database to keep the cache in, the cache is kept in memory when empty")
+	cacheEntries := flag.Int("cache-entries", 10000, "maximum number of responses kept by the memory cache")
+	liveTTL := flag.Duration("ttl", 3*time.Second, "how long responses about live sessions are served from the cache")
+	scheduleTTL := flag.Duration("schedule-ttl", time.Hour, "how long meetings and sessions are served from the cache")
+	interval := flag.Duration("interval", openf1go.DefaultDownloadOptions().RequestInterval, "minimum time between two requests to the API")
+	upstream := flag.String("upstream", openf1go.DefaultBaseURL, "base URL of the OpenF1 API")
+	flag.Parse()
+
+	// Stop cleanly on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var c cache = newMemoryCache(*cacheEntries)
+	if *db != "" {
+		sqlite, err := openSQLiteCache(*db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "openf1-proxy:", err)
+			os.Exit(1)
+		}
+		c = sqlite
+	}
+	defer c.Close()
+
+	client := openf1go.New(
+		openf1go.WithBaseURL(*upstream),
+		openf1go.WithTransport(newPacedTransport(http.DefaultTransport, *interval)),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", newProxy(client, c, *liveTTL, *scheduleTTL))
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(os.Stderr, "openf1-proxy: serving %s on %s/v1\n", *upstream, *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(os.Stderr, "openf1-proxy:", err)
+		os.Exit(1)
+	}
+}
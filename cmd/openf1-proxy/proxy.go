@@ -0,0 +1,309 @@
+package main
+
+// Serves the API paths from the cache, forwarding misses upstream through the client.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/internal/query"
+	"github.com/stephenhoran/open-f1-go/storage"
+)
+
+// pruneEvery is the number of responses stored between two removals of stale entries
+const pruneEvery = 1000
+
+// staleFor is how long stale entries are kept to answer when the API fails
+const staleFor = time.Hour
+
+// proxy mirrors the /v1/<endpoint> paths of the API
+type proxy struct {
+	client      *openf1go.Client
+	cache       cache
+	liveTTL     time.Duration // How long responses about live or unknown sessions are served from the cache
+	scheduleTTL time.Duration // How long meetings and sessions responses are served from the cache
+	group       group         // Requests to the API in flight, by cache key
+
+	mu     sync.Mutex
+	ended  map[query.Filter]bool // Session and meeting filters known to select sessions that are over
+	stored int                   // Responses stored since the last prune
+}
+
+// newProxy creates a proxy forwarding misses with the client
+func newProxy(client *openf1go.Client, c cache, liveTTL, scheduleTTL time.Duration) *proxy {
+	return &proxy{
+		client:      client,
+		cache:       c,
+		liveTTL:     liveTTL,
+		scheduleTTL: scheduleTTL,
+		group:       group{calls: map[string]*call{}},
+		ended:       map[query.Filter]bool{},
+	}
+}
+
+// ServeHTTP answers a request from the cache, or from the API when the cached response is missing or stale.
+// The X-Cache header tells how it was answered: HIT, MISS, SHARED for a miss answered by a request already
+// in flight, or STALE when the API failed and an older response was served instead.
+// Client errors of the API are passed on with their status and body.
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	endpoint, ok := strings.CutPrefix(r.URL.Path, "/v1/")
+	if !ok || !slices.Contains(openf1go.ArchiveEndpoints, endpoint) {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Queries differing only in ordering or escaping share a cache entry
+	canonical, err := query.Canonical(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := endpoint + "?" + canonical
+
+	cached, found, err := p.cache.get(r.Context(), key)
+	if err != nil {
+		log.Printf("reading %s from the cache: %v", key, err)
+	}
+	if found && cached.fresh(time.Now()) {
+		write(w, "HIT", cached.body)
+		return
+	}
+
+	// The fetch outlives the request that started it as other requests may be waiting on it
+	ctx := context.WithoutCancel(r.Context())
+	body, err, shared := p.group.do(key, func() ([]byte, error) {
+		return p.fetch(ctx, endpoint, canonical, key)
+	})
+
+	var statusErr *openf1go.StatusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 &&
+		statusErr.StatusCode != http.StatusTooManyRequests:
+		// The query itself is wrong, a cached response would not help
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusErr.StatusCode)
+		w.Write(body)
+	case err != nil && found:
+		write(w, "STALE", cached.body)
+	case errors.Is(err, openf1go.ErrRateLimited):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	case shared:
+		write(w, "SHARED", body)
+	default:
+		write(w, "MISS", body)
+	}
+}
+
+// write writes a response body, JSON unless the query asked for CSV
+func write(w http.ResponseWriter, status string, body []byte) {
+	contentType := "application/json"
+	if trimmed := strings.TrimSpace(string(body[:min(len(body), 16)])); trimmed != "" && trimmed[0] != '[' && trimmed[0] != '{' {
+		contentType = "text/csv"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Cache", status)
+	w.Write(body)
+}
+
+// fetch asks the API for the records of an endpoint matching the canonical query and caches the response.
+// The body of failed requests is returned along with the error.
+func (p *proxy) fetch(ctx context.Context, endpoint, canonical, key string) ([]byte, error) {
+	filters, err := query.Parse(canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]openf1go.Arg, len(filters))
+	for i, f := range filters {
+		args[i] = openf1go.Arg{Key: f.Field, Value: f.Value}
+		if f.Op != "=" {
+			args[i].Key += f.Op
+		}
+	}
+
+	body, err := p.client.Raw(ctx, endpoint, args)
+	if err != nil {
+		return body, err
+	}
+
+	now := time.Now()
+	e := entry{body: body, fetched: now}
+	if ttl := p.ttl(ctx, endpoint, filters, now); ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+	if err := p.cache.put(ctx, key, e); err != nil {
+		log.Printf("writing %s to the cache: %v", key, err)
+	}
+
+	// Remove stale entries from time to time
+	p.mu.Lock()
+	p.stored++
+	prune := p.stored >= pruneEvery
+	if prune {
+		p.stored = 0
+	}
+	p.mu.Unlock()
+	if prune {
+		if err := p.cache.prune(ctx, now.Add(-staleFor)); err != nil {
+			log.Printf("pruning the cache: %v", err)
+		}
+	}
+
+	return body, nil
+}
+
+// ttl returns how long a response can be served from the cache, zero when it never goes stale.
+// Only responses that can no longer change are final: those pinned to sessions or meetings that are over, or
+// bounded by a date older than the time sessions may still receive data. Anything else may be about a live
+// session, such as queries for the latest session or for a driver across sessions.
+func (p *proxy) ttl(ctx context.Context, endpoint string, filters []query.Filter, now time.Time) time.Duration {
+	for _, f := range filters {
+		if f.Value == "latest" {
+			return p.liveTTL
+		}
+	}
+
+	// The schedule may change even for past meetings and sessions
+	if endpoint == "meetings" || endpoint == "sessions" {
+		return p.scheduleTTL
+	}
+
+	pinned := false
+	for _, f := range filters {
+		switch {
+		case (f.Field == "session_key" || f.Field == "meeting_key") && f.Op == "=":
+			if !p.sessionsEnded(ctx, f) {
+				return p.liveTTL
+			}
+			pinned = true
+
+		case f.Field == "date" && (f.Op == "<" || f.Op == "<="):
+			if bound, err := parseDate(f.Value); err == nil && bound.Before(now.Add(-storage.LiveMargin)) {
+				pinned = true
+			}
+		}
+	}
+
+	if pinned {
+		return 0
+	}
+	return p.liveTTL
+}
+
+// sessionsEnded reports whether every session selected by a session_key or meeting_key filter is over and its data final
+func (p *proxy) sessionsEnded(ctx context.Context, f query.Filter) bool {
+	p.mu.Lock()
+	ended := p.ended[f]
+	p.mu.Unlock()
+	if ended {
+		return true
+	}
+
+	if _, err := strconv.Atoi(f.Value); err != nil {
+		return false
+	}
+	body, err := p.client.Raw(ctx, "sessions", []openf1go.Arg{{Key: f.Field, Value: f.Value}})
+	if err != nil {
+		return false
+	}
+	var sessions openf1go.SessionResponse
+	if err := json.Unmarshal(body, &sessions); err != nil || len(sessions) == 0 {
+		return false
+	}
+
+	// Sessions still receive data for a while after their scheduled end
+	for _, session := range sessions {
+		if session.DateEnd.IsZero() || time.Now().Before(session.DateEnd.Add(storage.LiveMargin)) {
+			return false
+		}
+	}
+
+	p.mu.Lock()
+	p.ended[f] = true
+	p.mu.Unlock()
+	return true
+}
+
+// parseDate parses a date filter value, with or without a time zone
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// group coalesces identical requests to the API so only one is in flight at a time
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call // Requests in flight by cache key
+}
+
+// call represents a request to the API in flight
+type call struct {
+	done chan struct{} // Closed once the request completed
+	body []byte
+	err  error
+}
+
+// do calls fn unless a call with the same key is in flight, in which case it waits for that call's result.
+// shared reports whether the result came from another call.
+func (g *group) do(key string, fn func() ([]byte, error)) (body []byte, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.body, c.err, true
+	}
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.body, c.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+
+	return c.body, c.err, false
+}
+
+// pacedTransport spaces out the requests sent to the API to stay below its rate limit
+type pacedTransport struct {
+	next   http.RoundTripper
+	ticker *time.Ticker
+}
+
+// newPacedTransport creates a transport sending at most one request per interval
+func newPacedTransport(next http.RoundTripper, interval time.Duration) *pacedTransport {
+	return &pacedTransport{next: next, ticker: time.NewTicker(interval)}
+}
+
+// RoundTrip waits for the next tick and sends the request
+func (t *pacedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case <-t.ticker.C:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/internal/query"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+const (
+	testLiveTTL     = 3 * time.Second
+	testScheduleTTL = time.Hour
+)
+
+// newTestProxy starts a fake API with a live session alongside the fixtures and a proxy in front of it
+func newTestProxy(t *testing.T) (*openf1test.Server, *proxy) {
+	t.Helper()
+
+	server := openf1test.NewServer()
+	t.Cleanup(server.Close)
+
+	start := time.Now().UTC().Add(-time.Hour)
+	live := openf1go.Session{SessionKey: 9999, MeetingKey: 1220, DateStart: start, DateEnd: start.Add(2 * time.Hour)}
+	if err := server.AddRecords("sessions", live); err != nil {
+		t.Fatal(err)
+	}
+
+	return server, newProxy(server.Client(), newMemoryCache(100), testLiveTTL, testScheduleTTL)
+}
+
+func TestProxyTTL(t *testing.T) {
+	_, p := newTestProxy(t)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		rawQuery string
+		want     time.Duration
+	}{
+		{name: "ended session", endpoint: "laps", rawQuery: "session_key=9158", want: 0},
+		{name: "ended meeting", endpoint: "laps", rawQuery: "meeting_key=1219&driver_number=1", want: 0},
+		{name: "past date bound", endpoint: "car_data", rawQuery: "driver_number=1&date<2023-09-18", want: 0},
+		{name: "latest session", endpoint: "laps", rawQuery: "session_key=latest", want: testLiveTTL},
+		{name: "live session", endpoint: "laps", rawQuery: "session_key=9999", want: testLiveTTL},
+		{name: "live meeting", endpoint: "laps", rawQuery: "meeting_key=1220", want: testLiveTTL},
+		{name: "ended session with a future date bound", endpoint: "laps", rawQuery: "session_key=9158&date<2099-01-01", want: 0},
+		{name: "unknown session", endpoint: "laps", rawQuery: "session_key=1", want: testLiveTTL},
+		{name: "no session", endpoint: "laps", rawQuery: "driver_number=1", want: testLiveTTL},
+		{name: "recent date bound", endpoint: "car_data", rawQuery: "date<" + now.UTC().Format(time.RFC3339), want: testLiveTTL},
+		{name: "lower date bound", endpoint: "car_data", rawQuery: "date>2023-09-17", want: testLiveTTL},
+		{name: "schedule", endpoint: "sessions", rawQuery: "session_key=9158", want: testScheduleTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := query.Parse(tt.rawQuery)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.ttl(context.Background(), tt.endpoint, filters, now); got != tt.want {
+				t.Errorf("ttl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyServeHTTP(t *testing.T) {
+	server, p := newTestProxy(t)
+	front := httptest.NewServer(p)
+	defer front.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		fault  int
+		status int
+		cache  string
+	}{
+		{name: "miss", path: "/v1/laps?session_key=9158&driver_number=1", status: http.StatusOK, cache: "MISS"},
+		{name: "hit in another order", path: "/v1/laps?driver_number=1&session_key=9158", status: http.StatusOK, cache: "HIT"},
+		{name: "unknown endpoint", path: "/v1/tyres", status: http.StatusNotFound},
+		{name: "upstream client error", path: "/v1/laps?session_key=9157", fault: http.StatusUnprocessableEntity, status: http.StatusUnprocessableEntity},
+		{name: "upstream rate limit", path: "/v1/laps?session_key=9157", fault: http.StatusTooManyRequests, status: http.StatusTooManyRequests},
+		{name: "upstream server error", path: "/v1/laps?session_key=9157", fault: http.StatusInternalServerError, status: http.StatusBadGateway},
+		{name: "live miss", path: "/v1/laps?session_key=latest", status: http.StatusOK, cache: "MISS"},
+		{name: "live stale", path: "/v1/laps?session_key=latest", fault: http.StatusInternalServerError, status: http.StatusOK, cache: "STALE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.fault != 0 {
+				server.FailNext(tt.fault, 1)
+			}
+			if tt.cache == "STALE" {
+				// Let the live response expire
+				key := "laps?session_key=latest"
+				e, _, _ := p.cache.get(context.Background(), key)
+				e.expires = time.Now().Add(-time.Second)
+				p.cache.put(context.Background(), key, e)
+			}
+
+			resp, err := http.Get(front.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if got := resp.Header.Get("X-Cache"); got != tt.cache {
+				t.Errorf("X-Cache = %q, want %q", got, tt.cache)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	c := newMemoryCache(2)
+
+	c.put(ctx, "a", entry{body: []byte("a")})
+	c.put(ctx, "b", entry{body: []byte("b")})
+	c.get(ctx, "a")
+	c.put(ctx, "c", entry{body: []byte("c")})
+
+	tests := []struct {
+		key   string
+		found bool
+	}{
+		{key: "a", found: true},
+		{key: "b", found: false},
+		{key: "c", found: true},
+	}
+
+	for _, tt := range tests {
+		if _, found, _ := c.get(ctx, tt.key); found != tt.found {
+			t.Errorf("get(%q) found = %v, want %v", tt.key, found, tt.found)
+		}
+	}
+
+	// Stale entries are pruned
+	c.put(ctx, "d", entry{body: []byte("d"), expires: time.Now().Add(-time.Hour)})
+	c.prune(ctx, time.Now())
+	if _, found, _ := c.get(ctx, "d"); found {
+		t.Error("prune() kept a stale entry")
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+// Defines the commands, each mapping its flags onto the filter struct of a client method.
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// command represents a subcommand querying one endpoint
+type command struct {
+	name    string                                 // Name of the command
+	summary string                                 // One line description shown in the usage
+	flags   []string                               // Names of the flags of the command, for completions
+	run     func(args []string, w io.Writer) error // Parses the flags, queries the API and writes the output
+}
+
+// commands lists every command in the order shown in the usage
+var commands = []command{
+	newCommand("meetings", "List meetings, e.g. --year 2023", (*openf1go.Client).GetMeetings),
+	newCommand("sessions", "List sessions, e.g. --year 2023 --session-name Race", (*openf1go.Client).GetSessions),
+	newCommand("drivers", "List the drivers of a session", (*openf1go.Client).GetDrivers),
+	newCommand("laps", "List laps, e.g. --session latest --driver-number 1", (*openf1go.Client).GetLaps),
+	newCommand("pits", "List pit stops", (*openf1go.Client).GetPits),
+	newCommand("stints", "List tyre stints", (*openf1go.Client).GetStints),
+	newCommand("weather", "List weather readings", (*openf1go.Client).GetWeather),
+	newCommand("race-control", "List race control messages", (*openf1go.Client).GetRaceControl),
+	newCommand("radio", "List team radio messages", (*openf1go.Client).GetTeamRadio),
+}
+
+// findCommand returns the command with the given name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// filterFlag represents a flag setting a field of a filter struct
+type filterFlag struct {
+	name  string // Name of the flag, the API field name with dashes
+	field int    // Index of the field in the filter struct
+}
+
+// filterFlags returns a flag for every field of the filter struct the client can filter on
+func filterFlags(model reflect.Type) []filterFlag {
+	flags := []filterFlag{}
+	for i := 0; i < model.NumField(); i++ {
+		f := model.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		// The client only filters on ints, strings and dates
+		switch {
+		case f.Type == reflect.TypeOf(time.Time{}), f.Type.Kind() == reflect.Int, f.Type.Kind() == reflect.String:
+			flags = append(flags, filterFlag{name: strings.ReplaceAll(tag, "_", "-"), field: i})
+		}
+	}
+	return flags
+}
+
+// newCommand creates a command querying an endpoint with the given client method
+func newCommand[T any, R ~[]T](name, summary string, get func(*openf1go.Client, T) (R, error)) command {
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	filters := filterFlags(model)
+
+	cmd := command{name: name, summary: summary}
+	for _, f := range filters {
+		cmd.flags = append(cmd.flags, f.name)
+	}
+
+	// Short aliases accepting "latest"
+	_, hasSession := model.FieldByName("SessionKey")
+	_, hasMeeting := model.FieldByName("MeetingKey")
+	if hasSession {
+		cmd.flags = append(cmd.flags, "session")
+	}
+	if hasMeeting {
+		cmd.flags = append(cmd.flags, "meeting")
+	}
+	cmd.flags = append(cmd.flags, "format", "columns", "tz", "base-url")
+
+	cmd.run = func(args []string, w io.Writer) error {
+		fs := flag.NewFlagSet("openf1 "+name, flag.ContinueOnError)
+		fs.SetOutput(os.Stderr)
+
+		values := make([]*string, len(filters))
+		for i, f := range filters {
+			usage := "filter on " + strings.ReplaceAll(f.name, "-", "_")
+			if isKeyField(model.Field(f.field).Name) {
+				usage = strings.ReplaceAll(f.name, "-", " ") + `, or "latest"`
+			}
+			values[i] = fs.String(f.name, "", usage)
+		}
+		var session, meeting *string
+		if hasSession {
+			session = fs.String("session", "", `session key, or "latest" (alias of --session-key)`)
+		}
+		if hasMeeting {
+			meeting = fs.String("meeting", "", `meeting key, or "latest" (alias of --meeting-key)`)
+		}
+		format := fs.String("format", "table", "output format: table, json or csv")
+		columns := fs.String("columns", "", "comma separated columns to show in table output")
+		tz := fs.String("tz", "UTC", `time zone dates are shown in, e.g. "Local" or "Asia/Singapore"`)
+		baseURL := fs.String("base-url", openf1go.DefaultBaseURL, "base URL of the OpenF1 API")
+
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		loc, err := time.LoadLocation(*tz)
+		if err != nil {
+			return err
+		}
+
+		// Set the fields of the filter from the flags, leaving the session and meeting keys to be resolved below
+		var filter T
+		v := reflect.ValueOf(&filter).Elem()
+		keys := map[string]string{} // Values of the key flags by field name
+		for i, f := range filters {
+			if *values[i] == "" {
+				continue
+			}
+			if name := model.Field(f.field).Name; isKeyField(name) {
+				keys[name] = *values[i]
+				continue
+			}
+			if err := setField(v.Field(f.field), *values[i]); err != nil {
+				return fmt.Errorf("--%s: %w", f.name, err)
+			}
+		}
+
+		client := openf1go.New(openf1go.WithBaseURL(*baseURL))
+
+		// Resolve the keys and their aliases, looking up the latest session or meeting when asked to
+		for _, k := range []struct {
+			field  string
+			flag   string
+			alias  *string
+			latest func() (int, error)
+		}{
+			{field: "SessionKey", flag: "session-key", alias: session, latest: func() (int, error) {
+				s, err := client.GetLatestSessions()
+				return s.SessionKey, err
+			}},
+			{field: "MeetingKey", flag: "meeting-key", alias: meeting, latest: func() (int, error) {
+				m, err := client.GetLatestMeeting()
+				return m.MeetingKey, err
+			}},
+		} {
+			name, value := k.flag, keys[k.field]
+			if k.alias != nil && *k.alias != "" {
+				alias := strings.TrimSuffix(k.flag, "-key")
+				if value != "" && value != *k.alias {
+					return fmt.Errorf("--%s and --%s disagree", alias, k.flag)
+				}
+				name, value = alias, *k.alias
+			}
+			if value == "" {
+				continue
+			}
+
+			key, err := resolveKey(value, k.latest)
+			if err != nil {
+				return fmt.Errorf("--%s: %w", name, err)
+			}
+			v.FieldByName(k.field).SetInt(int64(key))
+		}
+
+		records, err := get(client, filter)
+		if err != nil {
+			return err
+		}
+
+		return write(w, []T(records), *format, *columns, loc)
+	}
+
+	return cmd
+}
+
+// isKeyField reports whether a filter field holds a session or meeting key, which accept "latest"
+func isKeyField(name string) bool {
+	return name == "SessionKey" || name == "MeetingKey"
+}
+
+// resolveKey parses a meeting or session key, calling latest for "latest"
+func resolveKey(value string, latest func() (int, error)) (int, error) {
+	if value == "latest" {
+		return latest()
+	}
+	return strconv.Atoi(value)
+}
+
+// setField parses a flag value into a field of a filter struct
+func setField(v reflect.Value, value string) error {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if t, err := time.Parse(layout, value); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid date %q, expected RFC 3339 or YYYY-MM-DD", value)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	}
+	return nil
+}
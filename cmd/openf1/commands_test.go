@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+// runCommand runs a command against the test server and decodes its JSON output into laps
+func runCommand(t *testing.T, server *openf1test.Server, name string, args ...string) (openf1go.LapsResponse, error) {
+	t.Helper()
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		t.Fatalf("findCommand(%q) found no command", name)
+	}
+
+	var b strings.Builder
+	if err := cmd.run(append(args, "--base-url", server.BaseURL(), "--format", "json"), &b); err != nil {
+		return nil, err
+	}
+
+	var laps openf1go.LapsResponse
+	if err := json.Unmarshal([]byte(b.String()), &laps); err != nil {
+		t.Fatalf("%s wrote invalid JSON: %v\n%s", name, err, b.String())
+	}
+	return laps, nil
+}
+
+func TestCommandArgs(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args []string
+		laps int    // Laps returned
+		err  string // Part of the error message, empty when the command succeeds
+	}{
+		{name: "no filter", laps: 18},
+		{name: "session key", args: []string{"--session-key", "9158"}, laps: 18},
+		{name: "latest session key", args: []string{"--session-key", "latest", "--driver-number", "1"}, laps: 6},
+		{name: "latest session", args: []string{"--session", "latest", "--driver-number", "1"}, laps: 6},
+		{name: "latest meeting key", args: []string{"--meeting-key", "latest", "--lap-number", "2"}, laps: 3},
+		{name: "latest meeting", args: []string{"--meeting", "latest", "--lap-number", "2"}, laps: 3},
+		{name: "session and session key agreeing", args: []string{"--session", "9158", "--session-key", "9158", "--driver-number", "44"}, laps: 6},
+		{name: "other session", args: []string{"--session", "1234"}, laps: 0},
+		{name: "session and session key disagreeing", args: []string{"--session", "latest", "--session-key", "9158"}, err: "--session and --session-key disagree"},
+		{name: "invalid session key", args: []string{"--session-key", "last"}, err: "--session-key"},
+		{name: "invalid session", args: []string{"--session", "last"}, err: "--session"},
+		{name: "invalid number", args: []string{"--lap-number", "two"}, err: "--lap-number"},
+		{name: "invalid date", args: []string{"--date-start", "yesterday"}, err: "invalid date"},
+		{name: "unknown flag", args: []string{"--driver", "1"}, err: "flag provided but not defined"},
+		{name: "invalid time zone", args: []string{"--tz", "Mars/Olympus"}, err: "Mars/Olympus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			laps, err := runCommand(t, server, "laps", tt.args...)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("laps %v returned %v, want an error containing %q", tt.args, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("laps %v returned %v", tt.args, err)
+			}
+			if len(laps) != tt.laps {
+				t.Errorf("laps %v returned %d laps, want %d", tt.args, len(laps), tt.laps)
+			}
+		})
+	}
+
+	// Help is reported for main to exit quietly
+	cmd, _ := findCommand("laps")
+	if err := cmd.run([]string{"-h"}, &strings.Builder{}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("laps -h returned %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestCommandFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+	}{
+		{name: "laps", include: []string{"session-key", "session", "meeting-key", "meeting", "driver-number", "date-start", "format", "columns", "tz", "base-url"}, exclude: []string{"segments-sector-1", "is-pit-out-lap", "lap-duration"}},
+		{name: "meetings", include: []string{"meeting-key", "meeting", "year"}, exclude: []string{"session-key", "session"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := findCommand(tt.name)
+			if !ok {
+				t.Fatalf("findCommand(%q) found no command", tt.name)
+			}
+			for _, name := range tt.include {
+				if indexOf(cmd.flags, name) < 0 {
+					t.Errorf("flags %v miss %q", cmd.flags, name)
+				}
+			}
+			for _, name := range tt.exclude {
+				if indexOf(cmd.flags, name) >= 0 {
+					t.Errorf("flags %v include %q", cmd.flags, name)
+				}
+			}
+		})
+	}
+
+	if _, ok := findCommand("unknown"); ok {
+		t.Error("findCommand() found an unknown command")
+	}
+}
+
+func TestSetField(t *testing.T) {
+	singapore := time.FixedZone("+08", 8*60*60)
+
+	tests := []struct {
+		name  string
+		field string
+		value string
+		want  any
+		ok    bool
+	}{
+		{name: "number", field: "LapNumber", value: "12", want: 12, ok: true},
+		{name: "invalid number", field: "LapNumber", value: "1.5"},
+		{name: "RFC 3339 date", field: "DateStart", value: "2023-09-17T20:03:36.304+08:00", want: time.Date(2023, 9, 17, 20, 3, 36, 304000000, singapore), ok: true},
+		{name: "date and time", field: "DateStart", value: "2023-09-17 12:03:36", want: time.Date(2023, 9, 17, 12, 3, 36, 0, time.UTC), ok: true},
+		{name: "date", field: "DateStart", value: "2023-09-17", want: time.Date(2023, 9, 17, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "invalid date", field: "DateStart", value: "17/09/2023"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lap openf1go.Lap
+			err := setField(reflect.ValueOf(&lap).Elem().FieldByName(tt.field), tt.value)
+			if (err == nil) != tt.ok {
+				t.Fatalf("setField() returned %v", err)
+			}
+			if !tt.ok {
+				return
+			}
+			switch got := reflect.ValueOf(lap).FieldByName(tt.field).Interface().(type) {
+			case time.Time:
+				if !got.Equal(tt.want.(time.Time)) {
+					t.Errorf("setField() set %v, want %v", got, tt.want)
+				}
+			default:
+				if got != tt.want {
+					t.Errorf("setField() set %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	var race openf1go.Session
+	if err := setField(reflect.ValueOf(&race).Elem().FieldByName("SessionName"), "Race"); err != nil || race.SessionName != "Race" {
+		t.Errorf("setField() of a string set %q, %v", race.SessionName, err)
+	}
+}
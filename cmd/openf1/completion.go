@@ -0,0 +1,95 @@
+package main
+
+// Generates shell completion scripts from the command definitions.
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completion writes the completion script of the shell named in args
+func completion(w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: openf1 completion bash|zsh|fish")
+	}
+
+	switch args[0] {
+	case "bash":
+		return bashCompletion(w)
+	case "zsh":
+		// zsh runs the bash script through its bash completion emulation
+		fmt.Fprintln(w, "autoload -U +X bashcompinit && bashcompinit")
+		return bashCompletion(w)
+	case "fish":
+		return fishCompletion(w)
+	}
+
+	return fmt.Errorf("unsupported shell %q, expected bash, zsh or fish", args[0])
+}
+
+// commandNames returns the names of every command including completion
+func commandNames() []string {
+	names := []string{}
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	return append(names, "completion")
+}
+
+// bashCompletion writes the bash completion script
+func bashCompletion(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# bash completion for openf1, load with: source <(openf1 completion bash)\n")
+	b.WriteString("_openf1() {\n")
+	b.WriteString("\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("\tif [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(commandNames(), " "))
+	b.WriteString("\t\treturn\n\tfi\n")
+
+	// Values of the flags with a fixed set of values
+	b.WriteString("\tcase \"$prev\" in\n")
+	b.WriteString("\t--format) COMPREPLY=($(compgen -W \"table json csv\" -- \"$cur\")); return ;;\n")
+	b.WriteString("\t--session|--meeting|--session-key|--meeting-key) COMPREPLY=($(compgen -W \"latest\" -- \"$cur\")); return ;;\n")
+	b.WriteString("\tesac\n")
+
+	b.WriteString("\tcase \"${COMP_WORDS[1]}\" in\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "\t%s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", cmd.name, "--"+strings.Join(cmd.flags, " --"))
+	}
+	b.WriteString("\tcompletion) COMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\")) ;;\n")
+	b.WriteString("\tesac\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -F _openf1 openf1\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fishCompletion writes the fish completion script
+func fishCompletion(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# fish completion for openf1, load with: openf1 completion fish | source\n")
+	b.WriteString("complete -c openf1 -f\n")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "complete -c openf1 -n __fish_use_subcommand -a %s -d %q\n", cmd.name, cmd.summary)
+		for _, flag := range cmd.flags {
+			fmt.Fprintf(&b, "complete -c openf1 -n '__fish_seen_subcommand_from %s' -l %s -r", cmd.name, flag)
+			switch flag {
+			case "format":
+				b.WriteString(" -a 'table json csv'")
+			case "session", "meeting", "session-key", "meeting-key":
+				b.WriteString(" -a latest")
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("complete -c openf1 -n __fish_use_subcommand -a completion -d \"Print a completion script\"\n")
+	b.WriteString("complete -c openf1 -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,66 @@
+// Command openf1 queries the OpenF1 API from the command line.
+//
+// Usage:
+//
+//	openf1 <command> [flags]
+//
+// Every command accepts a flag per filter field of its model, named after the API field with dashes,
+// e.g. openf1 laps --session latest --driver-number 1 --lap-number 10.
+// Run openf1 <command> -h for the flags of a command and openf1 completion bash|zsh|fish for shell completions.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := os.Args[1], os.Args[2:]
+
+	switch name {
+	case "-h", "--help", "help":
+		usage()
+		return
+	case "completion":
+		if err := completion(os.Stdout, args); err != nil {
+			fmt.Fprintln(os.Stderr, "openf1:", err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "openf1: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(args, os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "openf1:", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the list of commands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: openf1 <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "  %-13s %s\n", "completion", "Print a bash, zsh or fish completion script")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run openf1 <command> -h for the flags of a command.")
+}
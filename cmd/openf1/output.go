@@ -0,0 +1,91 @@
+package main
+
+// Writes query results as a table, JSON or CSV.
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stephenhoran/open-f1-go/export"
+)
+
+// tableTimeFormat is the layout dates are shown with in tables
+const tableTimeFormat = "2006-01-02 15:04:05.000"
+
+// write writes the records in the given format
+func write[T any](w io.Writer, records []T, format, columns string, loc *time.Location) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "csv":
+		return export.WriteCSV(w, records, export.WithLocation(loc))
+	case "table":
+		return writeTable(w, records, columns, loc)
+	}
+	return fmt.Errorf("unknown format %q, expected table, json or csv", format)
+}
+
+// writeTable writes the records as an aligned table, rendered from their CSV form so both share formatting.
+// Without a column list, the flattened mini-sector segment columns are left out as they are too wide for a terminal.
+func writeTable[T any](w io.Writer, records []T, columns string, loc *time.Location) error {
+	var buf bytes.Buffer
+	if err := export.WriteCSV(&buf, records, export.WithTimeFormat(tableTimeFormat), export.WithLocation(loc)); err != nil {
+		return err
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Pick the columns to show
+	header := rows[0]
+	selected := []int{}
+	if columns != "" {
+		for _, name := range strings.Split(columns, ",") {
+			i := indexOf(header, strings.TrimSpace(name))
+			if i < 0 {
+				return fmt.Errorf("unknown column %q, available columns: %s", name, strings.Join(header, ", "))
+			}
+			selected = append(selected, i)
+		}
+	} else {
+		for i, name := range header {
+			if !strings.HasPrefix(name, "segments_") {
+				selected = append(selected, i)
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		cells := make([]string, len(selected))
+		for i, column := range selected {
+			cells[i] = row[column]
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// indexOf returns the index of a value in a list, -1 when missing
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// testLaps returns two laps with mini-sector data
+func testLaps() openf1go.LapsResponse {
+	start := time.Date(2023, 9, 17, 12, 3, 36, 304000000, time.UTC)
+	return openf1go.LapsResponse{
+		{SessionKey: 9158, DriverNumber: 1, LapNumber: 3, DateStart: start, LapDuration: 98.424, SegmentsSector1: []int{2049, 2051}},
+		{SessionKey: 9158, DriverNumber: 44, LapNumber: 3, DateStart: start.Add(3534 * time.Millisecond), LapDuration: 117.929, SegmentsSector1: []int{2048}},
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	singapore, err := time.LoadLocation("Asia/Singapore")
+	if err != nil {
+		t.Skip("time zone data unavailable:", err)
+	}
+
+	tests := []struct {
+		name    string
+		columns string
+		loc     *time.Location
+		want    string
+		err     string
+	}{
+		{
+			name:    "selected columns",
+			columns: "driver_number, lap_number,lap_duration",
+			loc:     time.UTC,
+			want:    "driver_number  lap_number  lap_duration\n1              3           98.424\n44             3           117.929\n",
+		},
+		{
+			name:    "dates in a time zone",
+			columns: "driver_number,date_start",
+			loc:     singapore,
+			want:    "driver_number  date_start\n1              2023-09-17 20:03:36.304\n44             2023-09-17 20:03:39.838\n",
+		},
+		{name: "unknown column", columns: "lap_time", loc: time.UTC, err: `unknown column "lap_time"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := write(&b, testLaps(), "table", tt.columns, tt.loc)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("write() returned %v, want an error containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("write() returned %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("write() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTableDefaultColumns(t *testing.T) {
+	var b strings.Builder
+	if err := write(&b, testLaps(), "table", "", time.UTC); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("write() wrote %d lines, want a header and 2 rows:\n%s", len(lines), b.String())
+	}
+	header := strings.Fields(lines[0])
+	if indexOf(header, "lap_duration") < 0 || indexOf(header, "date_start") < 0 {
+		t.Errorf("table header %v misses columns", header)
+	}
+	for _, column := range header {
+		if strings.HasPrefix(column, "segments_") {
+			t.Errorf("table shows mini-sector column %q by default", column)
+		}
+	}
+
+	// Without records only the header is written
+	b.Reset()
+	if err := write(&b, openf1go.LapsResponse{}, "table", "", time.UTC); err != nil || strings.Join(strings.Fields(b.String()), " ") != strings.Join(header, " ") {
+		t.Errorf("write() of no records wrote %q, %v", b.String(), err)
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	var b strings.Builder
+	if err := write(&b, testLaps(), "json", "", time.UTC); err != nil {
+		t.Fatal(err)
+	}
+	var laps openf1go.LapsResponse
+	if err := json.Unmarshal([]byte(b.String()), &laps); err != nil || len(laps) != 2 || laps[1].DriverNumber != 44 {
+		t.Errorf("write() as JSON = %s, %v", b.String(), err)
+	}
+	if !strings.Contains(b.String(), "\n  {") {
+		t.Errorf("write() as JSON is not indented: %s", b.String())
+	}
+
+	b.Reset()
+	if err := write(&b, testLaps(), "csv", "", time.UTC); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(rows) != 3 || !strings.Contains(rows[0], "segments_sector_1") || !strings.Contains(rows[1], "2023-09-17T12:03:36.304Z") {
+		t.Errorf("write() as CSV =\n%s", b.String())
+	}
+
+	if err := write(&b, testLaps(), "yaml", "", time.UTC); err == nil || !strings.Contains(err.Error(), `unknown format "yaml"`) {
+		t.Errorf("write() as YAML returned %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package openf1go
+
+// Provides the data sources a Client answers queries from: the HTTP API or session archives on disk.
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/stephenhoran/open-f1-go/internal/query"
+)
+
+// DataSource answers API queries. Fetch returns the JSON array the API would return for the URL.
+type DataSource interface {
+	Fetch(ctx context.Context, url *url.URL) ([]byte, error)
+}
+
+// HTTPDataSource answers queries from the API over HTTP
+type HTTPDataSource struct {
+	Client *http.Client // HTTP client used to make requests, one with the same 15 second timeout as New when nil
+}
+
+// Fetch makes a GET request for the URL
+func (s HTTPDataSource) Fetch(ctx context.Context, url *url.URL) ([]byte, error) {
+	client := s.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return getHTTPRequest(ctx, client, url)
+}
+
+// ArchiveDataSource answers queries from session archives written by DownloadSession.
+// Endpoint files are read on first use and kept in memory, except car data and location which hold most of
+// a session's records: their files are read again for every query and filtered as they are read.
+type ArchiveDataSource struct {
+	archives []archive // Archives found on disk, in the order they were found
+
+	mu sync.Mutex
+}
+
+// archive represents a single session archive
+type archive struct {
+	dir      string                      // Directory holding the archive
+	manifest Manifest                    // Manifest of the archive
+	records  map[string][]map[string]any // Decoded records per endpoint, filled on first use except for chunked endpoints
+}
+
+// NewArchiveDataSource opens the session archives in the given directories.
+// Each directory may be an archive itself or hold archives in its subdirectories.
+func NewArchiveDataSource(dirs ...string) (*ArchiveDataSource, error) {
+	s := &ArchiveDataSource{}
+
+	for _, dir := range dirs {
+		// The directory is an archive
+		if manifest, err := ReadManifest(dir); err == nil {
+			s.archives = append(s.archives, archive{dir: dir, manifest: manifest, records: map[string][]map[string]any{}})
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		// The directory holds archives
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			sub := filepath.Join(dir, entry.Name())
+			manifest, err := ReadManifest(sub)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			s.archives = append(s.archives, archive{dir: sub, manifest: manifest, records: map[string][]map[string]any{}})
+		}
+	}
+
+	if len(s.archives) == 0 {
+		return nil, ErrNoArchives
+	}
+
+	return s, nil
+}
+
+// Sessions returns the sessions held by the archives
+func (s *ArchiveDataSource) Sessions() SessionResponse {
+	sessions := SessionResponse{}
+	for _, a := range s.archives {
+		sessions = append(sessions, a.manifest.Session)
+	}
+	return sessions
+}
+
+// Fetch answers the query of the URL from the archives, honouring equality, comparison operators and "latest"
+func (s *ArchiveDataSource) Fetch(ctx context.Context, url *url.URL) ([]byte, error) {
+	endpoint := path.Base(url.Path)
+	if !isArchiveEndpoint(endpoint) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEndpoint, endpoint)
+	}
+
+	filters, err := query.Parse(url.RawQuery)
+	if err != nil {
+		return nil, err
+	}
+	filters = s.resolveLatest(filters)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matched := []map[string]any{}
+	meetings := map[any]bool{} // Meetings already matched, as every archived session of a meeting holds it
+	keep := func(record map[string]any) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !query.Match(record, filters) {
+			return nil
+		}
+		if endpoint == "meetings" {
+			if meetings[record["meeting_key"]] {
+				return nil
+			}
+			meetings[record["meeting_key"]] = true
+		}
+		matched = append(matched, record)
+		return nil
+	}
+
+	for i := range s.archives {
+		a := &s.archives[i]
+
+		// Skip archives of other sessions without reading their files
+		if !query.Match(map[string]any{"session_key": float64(a.manifest.SessionKey), "meeting_key": float64(a.manifest.MeetingKey)}, keyFilters(filters)) {
+			continue
+		}
+
+		// Telemetry is too large to keep in memory and is filtered while it is read
+		if chunkedEndpoints[endpoint] {
+			if err := a.each(endpoint, keep); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		records, err := a.load(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if err := keep(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return json.Marshal(matched)
+}
+
+// resolveLatest replaces "latest" meeting and session keys with the keys of the most recent archived session
+func (s *ArchiveDataSource) resolveLatest(filters []query.Filter) []query.Filter {
+	latest := s.archives[0].manifest
+	for _, a := range s.archives[1:] {
+		if a.manifest.Session.DateStart.After(latest.Session.DateStart) {
+			latest = a.manifest
+		}
+	}
+
+	resolved := make([]query.Filter, len(filters))
+	for i, f := range filters {
+		switch {
+		case f.Value != "latest":
+		case f.Field == "meeting_key":
+			f.Value = strconv.Itoa(latest.MeetingKey)
+		case f.Field == "session_key":
+			f.Value = strconv.Itoa(latest.SessionKey)
+		}
+		resolved[i] = f
+	}
+
+	return resolved
+}
+
+// keyFilters returns the filters on meeting and session keys
+func keyFilters(filters []query.Filter) []query.Filter {
+	keys := []query.Filter{}
+	for _, f := range filters {
+		if f.Field == "meeting_key" || f.Field == "session_key" {
+			keys = append(keys, f)
+		}
+	}
+	return keys
+}
+
+// load returns the records of an endpoint, reading and verifying its file on first use
+func (a *archive) load(endpoint string) ([]map[string]any, error) {
+	if records, ok := a.records[endpoint]; ok {
+		return records, nil
+	}
+
+	records := []map[string]any{}
+	err := a.each(endpoint, func(record map[string]any) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.records[endpoint] = records
+	return records, nil
+}
+
+// each reads the file of an endpoint and calls fn for every record, then verifies the checksum of the file.
+// Archives written before an endpoint was added simply have no records for it.
+func (a *archive) each(endpoint string, fn func(record map[string]any) error) error {
+	file, ok := a.manifest.Endpoints[endpoint]
+	if !ok {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Join(a.dir, file.File))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Hash the compressed file while reading it
+	hash := sha256.New()
+	gz, err := gzip.NewReader(io.TeeReader(f, hash))
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Drain the trailing gzip data so the whole file is hashed
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return err
+	}
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != file.SHA256 {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, filepath.Join(a.dir, file.File))
+	}
+
+	return nil
+}
+
+// isArchiveEndpoint reports whether the endpoint is written to session archives
+func isArchiveEndpoint(endpoint string) bool {
+	for _, e := range ArchiveEndpoints {
+		if e == endpoint {
+			return true
+		}
+	}
+	return false
+}
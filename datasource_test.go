@@ -0,0 +1,186 @@
+package openf1go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHTTPDataSourceFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") != "" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`[{"driver_number":1}]`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		source HTTPDataSource
+		query  string
+		ok     bool
+	}{
+		{name: "default client", source: HTTPDataSource{}, ok: true},
+		{name: "custom client", source: HTTPDataSource{Client: &http.Client{}}, ok: true},
+		{name: "custom client timeout", source: HTTPDataSource{Client: &http.Client{Timeout: 10 * time.Millisecond}}, query: "slow=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(server.URL + "/v1/drivers?" + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := tt.source.Fetch(context.Background(), u)
+			if (err == nil) != tt.ok {
+				t.Fatalf("Fetch() returned %v", err)
+			}
+			if tt.ok && string(body) != `[{"driver_number":1}]` {
+				t.Errorf("Fetch() = %s", body)
+			}
+		})
+	}
+
+	// Without a client the source times out like a client created by New
+	if defaultHTTPClient.Timeout != New().client.Timeout || defaultHTTPClient.Timeout == 0 {
+		t.Errorf("default HTTP client timeout = %v, want %v", defaultHTTPClient.Timeout, New().client.Timeout)
+	}
+}
+
+// writeTestArchive writes an archive of a session holding the given raw records per endpoint
+func writeTestArchive(t *testing.T, dir string, session Session, records map[string][]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := Manifest{SessionKey: session.SessionKey, MeetingKey: session.MeetingKey, Session: session, Endpoints: map[string]ArchiveFile{}}
+	for endpoint, lines := range records {
+		w, err := newArchiveWriter(filepath.Join(dir, ArchiveFileName(endpoint)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := []json.RawMessage{}
+		for _, line := range lines {
+			raw = append(raw, json.RawMessage(line))
+		}
+		if err := w.write(raw); err != nil {
+			t.Fatal(err)
+		}
+		if manifest.Endpoints[endpoint], err = w.close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveDataSourceFetch(t *testing.T) {
+	dir := t.TempDir()
+	meeting := `{"meeting_key":1219,"meeting_name":"Singapore Grand Prix"}`
+	start := time.Date(2023, 9, 16, 13, 0, 0, 0, time.UTC)
+	writeTestArchive(t, filepath.Join(dir, "9157"), Session{SessionKey: 9157, MeetingKey: 1219, DateStart: start}, map[string][]string{
+		"meetings": {meeting},
+		"car_data": {`{"session_key":9157,"meeting_key":1219,"driver_number":1,"speed":250}`},
+	})
+	writeTestArchive(t, filepath.Join(dir, "9158"), Session{SessionKey: 9158, MeetingKey: 1219, DateStart: start.Add(24 * time.Hour)}, map[string][]string{
+		"meetings": {meeting},
+		"car_data": {`{"session_key":9158,"meeting_key":1219,"driver_number":1,"speed":300}`, `{"session_key":9158,"meeting_key":1219,"driver_number":44,"speed":310}`},
+	})
+
+	source, err := NewArchiveDataSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		records int
+	}{
+		{name: "meetings", path: "/v1/meetings", records: 1},
+		{name: "meeting by key", path: "/v1/meetings?meeting_key=1219", records: 1},
+		{name: "car data of a session", path: "/v1/car_data?session_key=9158", records: 2},
+		{name: "car data of a meeting", path: "/v1/car_data?meeting_key=1219&speed>=300", records: 2},
+		{name: "car data of a driver", path: "/v1/car_data?session_key=latest&driver_number=44", records: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse("http://archive" + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := source.Fetch(context.Background(), u)
+			if err != nil {
+				t.Fatalf("Fetch() returned %v", err)
+			}
+			var records []map[string]any
+			if err := json.Unmarshal(body, &records); err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != tt.records {
+				t.Errorf("Fetch() returned %d records, want %d: %s", len(records), tt.records, body)
+			}
+		})
+	}
+
+	// Telemetry is not kept in memory between queries
+	for _, a := range source.archives {
+		if _, ok := a.records["car_data"]; ok {
+			t.Errorf("archive %s keeps its car data in memory", a.dir)
+		}
+	}
+
+	// A damaged file is reported once read
+	if err := os.WriteFile(filepath.Join(dir, "9158", ArchiveFileName("car_data")), []byte("not gzip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("http://archive/v1/car_data?session_key=9158")
+	if _, err := source.Fetch(context.Background(), u); err == nil {
+		t.Error("Fetch() of a damaged file returned no error")
+	}
+}
+
+func TestArchiveDataSourceChecksum(t *testing.T) {
+	dir := t.TempDir()
+	writeTestArchive(t, dir, Session{SessionKey: 9158, MeetingKey: 1219}, map[string][]string{
+		"car_data": {`{"session_key":9158,"meeting_key":1219,"driver_number":1,"speed":300}`},
+	})
+
+	// Record another checksum in the manifest
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := manifest.Endpoints["car_data"]
+	file.SHA256 = "0000"
+	manifest.Endpoints["car_data"] = file
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewArchiveDataSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("http://archive/v1/car_data?session_key=9158")
+	if _, err := source.Fetch(context.Background(), u); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Fetch() returned %v, want ErrChecksumMismatch", err)
+	}
+}
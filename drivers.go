@@ -45,7 +45,7 @@ func (c *Client) GetDrivers(driver Driver) (DriversResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +80,7 @@ func (c *Client) GetDriver(driver Driver) (Driver, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return Driver{}, err
 	}
@@ -112,7 +112,7 @@ func (c *Client) GetLatestDrivers() (DriversResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return DriversResponse{}, err
 	}
@@ -3,3 +3,15 @@ package openf1go
 import "errors"
 
 var ErrDriverNumberMissing = errors.New("driver number is missing")
+
+var ErrSessionKeyMissing = errors.New("session key is missing")
+
+var ErrRateLimited = errors.New("rate limited by the API")
+
+var ErrUnexpectedStatus = errors.New("unexpected response status")
+var ErrSessionNotFound = errors.New("session not found")
+var ErrNoArchives = errors.New("no session archives found")
+var ErrUnknownEndpoint = errors.New("unknown endpoint")
+var ErrChecksumMismatch = errors.New("archive file checksum mismatch")
+var ErrNoLapData = errors.New("no timing data for lap")
+var ErrInvalidDownloadOptions = errors.New("download interval and chunk must not be negative")
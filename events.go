@@ -0,0 +1,371 @@
+package openf1go
+
+// Provides a single stream of time ordered events across endpoints, polled from the latest session.
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// EventKind identifies the endpoint an event originates from
+type EventKind string
+
+const (
+	EventCarData     EventKind = "car_data"     // Data holds a CarData
+	EventDriver      EventKind = "drivers"      // Data holds a Driver
+	EventInterval    EventKind = "intervals"    // Data holds an Interval
+	EventLap         EventKind = "laps"         // Data holds a Lap
+	EventLocation    EventKind = "location"     // Data holds a Location
+	EventPit         EventKind = "pit"          // Data holds a Pit
+	EventPosition    EventKind = "position"     // Data holds a Position
+	EventRaceControl EventKind = "race_control" // Data holds a RaceControl
+	EventStint       EventKind = "stints"       // Data holds a Stint
+	EventTeamRadio   EventKind = "team_radio"   // Data holds a TeamRadio
+	EventWeather     EventKind = "weather"      // Data holds a Weather
+	EventError       EventKind = "error"        // Data holds the error that interrupted polling
+)
+
+// Event represents a single record from any endpoint
+type Event struct {
+	Date time.Time // Time the record applies to, zero when the endpoint has no date
+	Kind EventKind // Endpoint the record originates from
+	Data any       // The record itself, see EventKind for the type held
+}
+
+// NewEvent wraps a record from any endpoint into an Event.
+// Stints and drivers carry no date and get a zero Date.
+func NewEvent(data any) Event {
+	switch d := data.(type) {
+	case CarData:
+		return Event{Date: d.Date, Kind: EventCarData, Data: d}
+	case Driver:
+		return Event{Kind: EventDriver, Data: d}
+	case Interval:
+		return Event{Date: d.Date, Kind: EventInterval, Data: d}
+	case Lap:
+		return Event{Date: d.DateStart, Kind: EventLap, Data: d}
+	case Location:
+		return Event{Date: d.Date, Kind: EventLocation, Data: d}
+	case Pit:
+		return Event{Date: d.Date, Kind: EventPit, Data: d}
+	case Position:
+		return Event{Date: d.Date, Kind: EventPosition, Data: d}
+	case RaceControl:
+		return Event{Date: d.Date, Kind: EventRaceControl, Data: d}
+	case Stint:
+		return Event{Kind: EventStint, Data: d}
+	case TeamRadio:
+		date, _ := time.Parse(time.RFC3339Nano, d.Date)
+		return Event{Date: date, Kind: EventTeamRadio, Data: d}
+	case Weather:
+		return Event{Date: d.Date, Kind: EventWeather, Data: d}
+	case error:
+		return Event{Date: time.Now(), Kind: EventError, Data: d}
+	}
+
+	return Event{Data: data}
+}
+
+// Apply ingests a single event into the live session.
+// Events of kinds the timing tower does not use are ignored.
+func (s *LiveSession) Apply(event Event) {
+	switch d := event.Data.(type) {
+	case Driver:
+		s.IngestDrivers(DriversResponse{d})
+	case Position:
+		s.IngestPositions(PostionsResponse{d})
+	case Interval:
+		s.IngestIntervals(IntervalsResponse{d})
+	case Lap:
+		s.IngestLaps(LapsResponse{d})
+	case Stint:
+		s.IngestStints(StintsReponse{d})
+	case Pit:
+		s.IngestPits(PitResponse{d})
+	}
+}
+
+// DefaultSubscribeInterval is the polling interval Subscribe uses when given none
+const DefaultSubscribeInterval = 4 * time.Second
+
+// stoppedAfter is how long after the latest lap start of any driver a driver more than a lap behind may have
+// started their latest lap before they are taken to have stopped, e.g. retired, and their laps are no longer refetched
+const stoppedAfter = 5 * time.Minute
+
+// Subscribe polls the latest session at the given interval and emits every new or updated record as an Event.
+// A zero or negative interval polls every DefaultSubscribeInterval.
+// After the first poll only records dated at or after the latest record already seen are fetched, along with the
+// laps and stints still in progress of drivers still running, starting over when a new session starts. Car data and location are not polled.
+// Each poll's events are emitted in time order, with drivers and stints, which carry no date, first.
+// Failed polls are reported as EventError events and polling continues. The channel is closed once the context is done.
+func (c *Client) Subscribe(ctx context.Context, interval time.Duration) <-chan Event {
+	events := make(chan Event, 256)
+
+	go func() {
+		defer close(events)
+
+		if interval <= 0 {
+			interval = DefaultSubscribeInterval
+		}
+
+		sub := newSubscription()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for _, event := range c.poll(ctx, sub) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// eventKey identifies a record so updates to the same record can be detected
+type eventKey struct {
+	kind         EventKind
+	driverNumber int
+	number       int       // Lap or stint number for records updated in place
+	date         time.Time // Date for dated records
+	message      string    // Message to tell apart race control records with the same date
+}
+
+// subscription holds the polling state of a Subscribe call
+type subscription struct {
+	session int                     // Session the state belongs to, zero until drivers were fetched
+	seen    map[eventKey]any        // Records already emitted, evicted once older than the cursors
+	since   map[EventKind]time.Time // Date of the latest record seen per dated endpoint
+	laps    map[int]Lap             // Latest lap seen per driver
+	stints  map[int]int             // Latest stint number seen per driver
+}
+
+// newSubscription creates the state of a subscription that has not polled yet
+func newSubscription() *subscription {
+	return &subscription{
+		seen:   map[eventKey]any{},
+		since:  map[EventKind]time.Time{},
+		laps:   map[int]Lap{},
+		stints: map[int]int{},
+	}
+}
+
+// dateArgs returns the filter fetching the records of a dated endpoint not older than the latest seen
+func (s *subscription) dateArgs(kind EventKind) []Arg {
+	if since, ok := s.since[kind]; ok {
+		return []Arg{{Key: "date>=", Value: since.UTC().Format(time.RFC3339Nano)}}
+	}
+	return nil
+}
+
+// stopped returns the drivers taken to have stopped: more than a lap behind the most advanced driver and with
+// a latest lap started more than stoppedAfter before the latest lap start of any driver, or without a start
+func (s *subscription) stopped() map[int]bool {
+	highest, newest := 0, time.Time{}
+	for _, lap := range s.laps {
+		highest = max(highest, lap.LapNumber)
+		if lap.DateStart.After(newest) {
+			newest = lap.DateStart
+		}
+	}
+
+	stopped := map[int]bool{}
+	for driverNumber, lap := range s.laps {
+		if lap.LapNumber < highest-1 && (lap.DateStart.IsZero() || newest.Sub(lap.DateStart) > stoppedAfter) {
+			stopped[driverNumber] = true
+		}
+	}
+	return stopped
+}
+
+// lapNumbers returns the latest lap number seen per driver
+func (s *subscription) lapNumbers() map[int]int {
+	numbers := map[int]int{}
+	for driverNumber, lap := range s.laps {
+		numbers[driverNumber] = lap.LapNumber
+	}
+	return numbers
+}
+
+// numberArgs returns the filter fetching the laps or stints still in progress, those at or after the lowest
+// latest number of any driver that has not stopped
+func numberArgs(key string, latest map[int]int, stopped map[int]bool) ([]Arg, int) {
+	lowest := 0
+	for driverNumber, number := range latest {
+		if !stopped[driverNumber] && (lowest == 0 || number < lowest) {
+			lowest = number
+		}
+	}
+	if lowest == 0 {
+		return nil, 0
+	}
+	return []Arg{{Key: key + ">=", Value: strconv.Itoa(lowest)}}, lowest
+}
+
+// advance moves the cursor of a dated endpoint forward to the given date
+func (s *subscription) advance(kind EventKind, date time.Time) {
+	if date.After(s.since[kind]) {
+		s.since[kind] = date
+	}
+}
+
+// evict forgets the records that can no longer be returned by a poll
+func (s *subscription) evict(lowestLap, lowestStint int) {
+	for key := range s.seen {
+		switch {
+		case key.kind == EventLap && key.number < lowestLap:
+		case key.kind == EventStint && key.number < lowestStint:
+		case !key.date.IsZero() && key.date.Before(s.since[key.kind]):
+		default:
+			continue
+		}
+		delete(s.seen, key)
+	}
+}
+
+// fetchLatest fetches the records of an endpoint in the latest session matching the extra args
+func fetchLatest[T any](ctx context.Context, c *Client, endpoint string, args []Arg) ([]T, error) {
+	body, err := c.Raw(ctx, endpoint, append(c.getLatestSessionArgs(), args...))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []T
+	if err := json.Unmarshal(body, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// poll fetches the latest session once and returns the records not returned by a previous poll ordered by date
+func (c *Client) poll(ctx context.Context, s *subscription) []Event {
+	events := []Event{}
+
+	// emit appends the event when the record is new or changed since it was last seen
+	emit := func(key eventKey, data any) {
+		if previous, ok := s.seen[key]; ok && reflect.DeepEqual(previous, data) {
+			return
+		}
+		s.seen[key] = data
+		events = append(events, NewEvent(data))
+	}
+
+	// fail records a failed poll
+	fail := func(err error) {
+		events = append(events, NewEvent(err))
+	}
+
+	if drivers, err := fetchLatest[Driver](ctx, c, "drivers", nil); err != nil {
+		fail(err)
+	} else {
+		// A new session started, its records are all new and its laps and stints count from one again
+		if len(drivers) > 0 && drivers[0].SessionKey != s.session {
+			*s = *newSubscription()
+			s.session = drivers[0].SessionKey
+		}
+		for _, d := range drivers {
+			emit(eventKey{kind: EventDriver, driverNumber: d.DriverNumber}, d)
+		}
+	}
+
+	if positions, err := fetchLatest[Position](ctx, c, "position", s.dateArgs(EventPosition)); err != nil {
+		fail(err)
+	} else {
+		for _, p := range positions {
+			emit(eventKey{kind: EventPosition, driverNumber: p.DriverNumber, date: p.Date}, p)
+			s.advance(EventPosition, p.Date)
+		}
+	}
+
+	if intervals, err := fetchLatest[Interval](ctx, c, "intervals", s.dateArgs(EventInterval)); err != nil {
+		fail(err)
+	} else {
+		for _, i := range intervals {
+			emit(eventKey{kind: EventInterval, driverNumber: i.DriverNumber, date: i.Date}, i)
+			s.advance(EventInterval, i.Date)
+		}
+	}
+
+	// Laps are updated in place until they are completed, refetch every lap still in progress
+	stopped := s.stopped()
+	lapArgs, lowestLap := numberArgs("lap_number", s.lapNumbers(), stopped)
+	if laps, err := fetchLatest[Lap](ctx, c, "laps", lapArgs); err != nil {
+		fail(err)
+	} else {
+		for _, l := range laps {
+			emit(eventKey{kind: EventLap, driverNumber: l.DriverNumber, number: l.LapNumber}, l)
+			if l.LapNumber >= s.laps[l.DriverNumber].LapNumber {
+				s.laps[l.DriverNumber] = l
+			}
+		}
+	}
+
+	// Stints get their end lap once the driver pits, refetch every stint still in progress
+	stintArgs, lowestStint := numberArgs("stint_number", s.stints, stopped)
+	if stints, err := fetchLatest[Stint](ctx, c, "stints", stintArgs); err != nil {
+		fail(err)
+	} else {
+		for _, st := range stints {
+			emit(eventKey{kind: EventStint, driverNumber: st.DriverNumber, number: st.StintNumber}, st)
+			s.stints[st.DriverNumber] = max(s.stints[st.DriverNumber], st.StintNumber)
+		}
+	}
+
+	if pits, err := fetchLatest[Pit](ctx, c, "pit", s.dateArgs(EventPit)); err != nil {
+		fail(err)
+	} else {
+		for _, p := range pits {
+			emit(eventKey{kind: EventPit, driverNumber: p.DriverNumber, number: p.LapNumber, date: p.Date}, p)
+			s.advance(EventPit, p.Date)
+		}
+	}
+
+	if raceControl, err := fetchLatest[RaceControl](ctx, c, "race_control", s.dateArgs(EventRaceControl)); err != nil {
+		fail(err)
+	} else {
+		for _, rc := range raceControl {
+			emit(eventKey{kind: EventRaceControl, date: rc.Date, message: rc.Message}, rc)
+			s.advance(EventRaceControl, rc.Date)
+		}
+	}
+
+	if teamRadio, err := fetchLatest[TeamRadio](ctx, c, "team_radio", s.dateArgs(EventTeamRadio)); err != nil {
+		fail(err)
+	} else {
+		for _, t := range teamRadio {
+			date := NewEvent(t).Date
+			emit(eventKey{kind: EventTeamRadio, driverNumber: t.DriverNumber, date: date}, t)
+			s.advance(EventTeamRadio, date)
+		}
+	}
+
+	if weather, err := fetchLatest[Weather](ctx, c, "weather", s.dateArgs(EventWeather)); err != nil {
+		fail(err)
+	} else {
+		for _, w := range weather {
+			emit(eventKey{kind: EventWeather, date: w.Date}, w)
+			s.advance(EventWeather, w.Date)
+		}
+	}
+
+	s.evict(lowestLap, lowestStint)
+
+	// Order the batch by date, records without a date first
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+
+	return events
+}
@@ -0,0 +1,219 @@
+package openf1go_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+// queryRecorder records the query of every request sent through it
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries map[string][]string // Raw queries per endpoint in request order
+}
+
+func (r *queryRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	endpoint := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	r.queries[endpoint] = append(r.queries[endpoint], req.URL.RawQuery)
+	r.mu.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// last returns the query of the latest request to an endpoint
+func (r *queryRecorder) last(endpoint string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queries := r.queries[endpoint]
+	if len(queries) == 0 {
+		return ""
+	}
+	return queries[len(queries)-1]
+}
+
+// drain collects events until none arrive for the given duration
+func drain(t *testing.T, events <-chan openf1go.Event, quiet time.Duration) []openf1go.Event {
+	t.Helper()
+
+	collected := []openf1go.Event{}
+	for {
+		select {
+		case event := <-events:
+			if event.Kind == openf1go.EventError {
+				t.Fatalf("Subscribe() emitted error %v", event.Data)
+			}
+			collected = append(collected, event)
+		case <-time.After(quiet):
+			return collected
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+
+	recorder := &queryRecorder{queries: map[string][]string{}}
+	client := server.Client(openf1go.WithTransport(recorder))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := client.Subscribe(ctx, 20*time.Millisecond)
+
+	// The first poll emits every record of the session once, in time order, and later polls emit nothing
+	first := drain(t, events, 300*time.Millisecond)
+	counts := map[openf1go.EventKind]int{}
+	for i, event := range first {
+		counts[event.Kind]++
+		if i > 0 && event.Date.Before(first[i-1].Date) {
+			t.Errorf("event %d at %v emitted after %v", i, event.Date, first[i-1].Date)
+		}
+	}
+
+	want := map[openf1go.EventKind]int{
+		openf1go.EventDriver: 3, openf1go.EventPosition: 5, openf1go.EventInterval: 30, openf1go.EventLap: 18,
+		openf1go.EventStint: 4, openf1go.EventPit: 1, openf1go.EventRaceControl: 8, openf1go.EventTeamRadio: 2,
+		openf1go.EventWeather: 6,
+	}
+	for kind, count := range want {
+		if counts[kind] != count {
+			t.Errorf("first poll emitted %d %s events, want %d", counts[kind], kind, count)
+		}
+	}
+
+	// Later polls only ask for records at or after the latest seen
+	tests := []struct {
+		endpoint string
+		filter   string
+	}{
+		{endpoint: "position", filter: "date>="},
+		{endpoint: "intervals", filter: "date>="},
+		{endpoint: "race_control", filter: "date>="},
+		{endpoint: "team_radio", filter: "date>="},
+		{endpoint: "weather", filter: "date>="},
+		{endpoint: "laps", filter: "lap_number>="},
+		{endpoint: "stints", filter: "stint_number>="},
+	}
+	for _, tt := range tests {
+		if query := recorder.last(tt.endpoint); !strings.Contains(query, tt.filter) {
+			t.Errorf("%s polled with %q, want a %s filter", tt.endpoint, query, tt.filter)
+		}
+	}
+
+	// New records and records updated in place are emitted once each
+	position := openf1go.Position{SessionKey: 9158, MeetingKey: 1219, DriverNumber: 44, Position: 2, Date: time.Date(2023, 9, 17, 13, 0, 0, 0, time.UTC)}
+	if err := server.AddRecords("position", position); err != nil {
+		t.Fatal(err)
+	}
+	stint := openf1go.Stint{SessionKey: 9158, MeetingKey: 1219, DriverNumber: 1, StintNumber: 3, Compound: "SOFT", LapStart: 40}
+	if err := server.AddRecords("stints", stint); err != nil {
+		t.Fatal(err)
+	}
+
+	next := drain(t, events, 300*time.Millisecond)
+	if len(next) != 2 || next[0].Kind != openf1go.EventStint || next[1].Kind != openf1go.EventPosition {
+		t.Fatalf("Subscribe() emitted %+v after adding a stint and a position", next)
+	}
+	if got := next[1].Data.(openf1go.Position); got != position {
+		t.Errorf("position event = %+v, want %+v", got, position)
+	}
+}
+
+func TestSubscribeSessionChange(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := server.Client().Subscribe(ctx, 20*time.Millisecond)
+	drain(t, events, 300*time.Millisecond)
+
+	// The next session starts and its first lap is emitted although earlier laps went up to lap 6
+	start := time.Date(2023, 9, 17, 15, 0, 0, 0, time.UTC)
+	records := []struct {
+		endpoint string
+		record   any
+	}{
+		{endpoint: "sessions", record: openf1go.Session{SessionKey: 9159, MeetingKey: 1219, DateStart: start, DateEnd: start.Add(time.Hour)}},
+		{endpoint: "drivers", record: openf1go.Driver{SessionKey: 9159, MeetingKey: 1219, DriverNumber: 1}},
+		{endpoint: "laps", record: openf1go.Lap{SessionKey: 9159, MeetingKey: 1219, DriverNumber: 1, LapNumber: 1, DateStart: start}},
+	}
+	for _, r := range records {
+		if err := server.AddRecords(r.endpoint, r.record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	next := drain(t, events, 300*time.Millisecond)
+	kinds := []openf1go.EventKind{}
+	for _, event := range next {
+		kinds = append(kinds, event.Kind)
+	}
+	if len(next) != 2 || next[0].Kind != openf1go.EventDriver || next[1].Kind != openf1go.EventLap {
+		t.Fatalf("Subscribe() emitted %v after the session changed, want a driver and a lap", kinds)
+	}
+}
+
+func TestSubscribeStoppedDriver(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+
+	recorder := &queryRecorder{queries: map[string][]string{}}
+	client := server.Client(openf1go.WithTransport(recorder))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := client.Subscribe(ctx, 20*time.Millisecond)
+	drain(t, events, 300*time.Millisecond)
+
+	// Drivers 1 and 16 run on to lap 12 while driver 44 stopped on lap 6
+	start := time.Date(2023, 9, 17, 12, 10, 0, 0, time.UTC)
+	for lap := 7; lap <= 12; lap++ {
+		for _, driverNumber := range []int{1, 16} {
+			record := openf1go.Lap{SessionKey: 9158, MeetingKey: 1219, DriverNumber: driverNumber, LapNumber: lap, DateStart: start.Add(time.Duration(lap-7) * 98 * time.Second)}
+			if err := server.AddRecords("laps", record); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if next := drain(t, events, 300*time.Millisecond); len(next) != 12 {
+		t.Fatalf("Subscribe() emitted %d events after adding 12 laps", len(next))
+	}
+	if query := recorder.last("laps"); !strings.Contains(query, "lap_number>=12") {
+		t.Errorf("laps polled with %q, want the laps from lap 12 on", query)
+	}
+}
+
+func TestSubscribeInterval(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{name: "zero", interval: 0},
+		{name: "negative", interval: -time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The first poll runs at once, later ones every DefaultSubscribeInterval
+			if first := drain(t, server.Client().Subscribe(ctx, tt.interval), 300*time.Millisecond); len(first) == 0 {
+				t.Error("Subscribe() emitted no events")
+			}
+		})
+	}
+}
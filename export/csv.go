@@ -0,0 +1,102 @@
+package export
+
+// Writes API models as CSV for spreadsheets.
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// WriteCSV writes values of a model as CSV with a header row named after the API fields.
+// Slices are flattened into one column per element, e.g. segments_sector_1_1, segments_sector_1_2, ...
+// Null values are written as empty cells.
+func WriteCSV[T any](w io.Writer, values []T, opts ...Option) error {
+	o := newOptions(opts)
+
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	if model.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, model)
+	}
+	fields := fieldsOf(model)
+
+	// Size each slice column to the longest slice across all values
+	widths := make([]int, len(fields))
+	for i, f := range fields {
+		t := model.Field(f.index).Type
+		if t.Kind() != reflect.Slice || t == rawMessageType {
+			continue
+		}
+		if isNested(t.Elem()) {
+			return fmt.Errorf("field %s: %w: %s", model.Field(f.index).Name, ErrUnsupportedType, t)
+		}
+		for _, value := range values {
+			widths[i] = max(widths[i], reflect.ValueOf(value).Field(f.index).Len())
+		}
+	}
+
+	cw := csv.NewWriter(w)
+
+	// Write the header
+	header := []string{}
+	for i, f := range fields {
+		t := model.Field(f.index).Type
+		switch {
+		case t.Kind() == reflect.Slice && t != rawMessageType:
+			for n := 1; n <= widths[i]; n++ {
+				header = append(header, f.name+"_"+strconv.Itoa(n))
+			}
+		case isNested(t):
+			return fmt.Errorf("field %s: %w: %s", model.Field(f.index).Name, ErrUnsupportedType, t)
+		default:
+			header = append(header, f.name)
+		}
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	// Write a row per value
+	record := make([]string, 0, len(header))
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		record = record[:0]
+
+		for i, f := range fields {
+			fv := v.Field(f.index)
+			if fv.Kind() == reflect.Slice && fv.Type() != rawMessageType {
+				for n := 0; n < widths[i]; n++ {
+					cell := ""
+					if n < fv.Len() {
+						cell, _ = o.formatScalar(f.name, fv.Index(n))
+					}
+					record = append(record, cell)
+				}
+				continue
+			}
+
+			cell, _ := o.formatScalar(f.name, fv)
+			record = append(record, cell)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// isNested reports whether values of the type cannot be written to a single cell
+func isNested(t reflect.Type) bool {
+	switch {
+	case t == timeType || t == rawMessageType:
+		return false
+	case t.Kind() == reflect.Struct, t.Kind() == reflect.Map, t.Kind() == reflect.Slice, t.Kind() == reflect.Pointer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package export
+
+import "errors"
+
+var (
+	ErrUnsupportedType = errors.New("unsupported field type")
+)
@@ -0,0 +1,75 @@
+package export
+
+// Writes API models as newline delimited JSON.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// WriteNDJSON writes every value of the sequence as a JSON object per line, keyed by the API field names.
+// Dates are formatted with the options and fields the API reports as null are written as null.
+// Use slices.Values to write a slice.
+func WriteNDJSON[T any](w io.Writer, values iter.Seq[T], opts ...Option) error {
+	o := newOptions(opts)
+
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	if model.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, model)
+	}
+	fields := fieldsOf(model)
+
+	bw := bufio.NewWriter(w)
+	var line bytes.Buffer
+
+	for value := range values {
+		v := reflect.ValueOf(value)
+		line.Reset()
+		line.WriteByte('{')
+
+		for i, f := range fields {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			name, _ := json.Marshal(f.name)
+			line.Write(name)
+			line.WriteByte(':')
+
+			data, err := o.marshalValue(f.name, v.Field(f.index))
+			if err != nil {
+				return fmt.Errorf("field %s: %w", model.Field(f.index).Name, err)
+			}
+			line.Write(data)
+		}
+
+		line.WriteString("}\n")
+		if _, err := bw.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// marshalValue encodes a field value as JSON, applying the time options and writing null for missing values
+func (o Options) marshalValue(name string, v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Type() == timeType, v.Type() == rawMessageType, nullWhenZero[name], v.Kind() == reflect.String && stringDates[name]:
+		s, ok := o.formatScalar(name, v)
+		if !ok {
+			return []byte("null"), nil
+		}
+		if v.Type() == rawMessageType {
+			return v.Bytes(), nil
+		}
+		if v.Type() == timeType || v.Kind() == reflect.String {
+			return json.Marshal(s)
+		}
+	}
+	return json.Marshal(v.Interface())
+}
@@ -0,0 +1,108 @@
+package export
+
+// Options shared by the text writers.
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options configures how the text writers format values
+type Options struct {
+	TimeFormat string         // Layout dates are formatted with, time.RFC3339Nano by default
+	Location   *time.Location // Time zone dates are converted to, UTC by default
+}
+
+// Option configures a text writer
+type Option func(*Options)
+
+// WithTimeFormat formats dates with the given layout, e.g. time.DateTime
+func WithTimeFormat(layout string) Option {
+	return func(o *Options) {
+		o.TimeFormat = layout
+	}
+}
+
+// WithLocation converts dates to the given time zone before formatting them
+func WithLocation(loc *time.Location) Option {
+	return func(o *Options) {
+		o.Location = loc
+	}
+}
+
+// newOptions applies the options on top of the defaults
+func newOptions(opts []Option) Options {
+	o := Options{TimeFormat: time.RFC3339Nano, Location: time.UTC}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// formatTime formats a date, returning false for zero dates
+func (o Options) formatTime(t time.Time) (string, bool) {
+	if t.IsZero() {
+		return "", false
+	}
+	return t.In(o.Location).Format(o.TimeFormat), true
+}
+
+// field describes a model field written by the text writers
+type field struct {
+	index int    // Index of the field in the model
+	name  string // Name of the field in the API
+}
+
+// fieldsOf returns the fields of a model the API returns, in declaration order
+func fieldsOf(model reflect.Type) []field {
+	fields := []field{}
+	for i := 0; i < model.NumField(); i++ {
+		if name := jsonName(model.Field(i)); name != "" {
+			fields = append(fields, field{index: i, name: name})
+		}
+	}
+	return fields
+}
+
+// formatScalar formats a non slice value as text, returning false when the value is null
+func (o Options) formatScalar(name string, v reflect.Value) (string, bool) {
+	switch {
+	case v.Type() == timeType:
+		return o.formatTime(v.Interface().(time.Time))
+	case v.Kind() == reflect.String && stringDates[name]:
+		// Dates that do not parse are written as sent, empty ones are null
+		if t, ok := parseDate(v.String()); ok {
+			return o.formatTime(t)
+		}
+		return v.String(), v.String() != ""
+	case v.Type() == rawMessageType:
+		raw := strings.TrimSpace(string(v.Bytes()))
+		if raw == "" || raw == "null" {
+			return "", false
+		}
+		// Unquote strings such as "+1 LAP"
+		var s string
+		if err := json.Unmarshal(v.Bytes(), &s); err == nil {
+			return s, true
+		}
+		return raw, true
+	case isInt(v.Kind()):
+		if v.Int() == 0 && nullWhenZero[name] {
+			return "", false
+		}
+		return strconv.FormatInt(v.Int(), 10), true
+	case v.Kind() == reflect.Float64 || v.Kind() == reflect.Float32:
+		if v.Float() == 0 && nullWhenZero[name] {
+			return "", false
+		}
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case v.Kind() == reflect.String:
+		return v.String(), true
+	}
+	return "", false
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func TestTextWritersTeamRadioDate(t *testing.T) {
+	singapore := time.FixedZone("SGT", 8*60*60)
+
+	tests := []struct {
+		name   string
+		date   string
+		opts   []Option
+		csv    string
+		ndjson string
+	}{
+		{
+			name:   "defaults",
+			date:   "2023-09-17T12:07:31.123000+00:00",
+			csv:    "2023-09-17T12:07:31.123Z",
+			ndjson: `"2023-09-17T12:07:31.123Z"`,
+		},
+		{
+			name:   "time format and location",
+			date:   "2023-09-17T12:07:31.123000+00:00",
+			opts:   []Option{WithTimeFormat(time.DateTime), WithLocation(singapore)},
+			csv:    "2023-09-17 20:07:31",
+			ndjson: `"2023-09-17 20:07:31"`,
+		},
+		{
+			name:   "missing",
+			csv:    "",
+			ndjson: "null",
+		},
+		{
+			name:   "not a date",
+			date:   "soon",
+			csv:    "soon",
+			ndjson: `"soon"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radio := []openf1go.TeamRadio{{Date: tt.date, DriverNumber: 1}}
+
+			var csv bytes.Buffer
+			if err := WriteCSV(&csv, radio, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			rows := strings.Split(strings.TrimSpace(csv.String()), "\n")
+			if cell, _, _ := strings.Cut(rows[1], ","); cell != tt.csv {
+				t.Errorf("CSV date = %q, want %q", cell, tt.csv)
+			}
+
+			var ndjson bytes.Buffer
+			if err := WriteNDJSON(&ndjson, slices.Values(radio), tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if want := `{"date":` + tt.ndjson + `,`; !strings.HasPrefix(ndjson.String(), want) {
+				t.Errorf("NDJSON = %s, want a line starting with %s", ndjson.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package export
+
+// Writes API models to Parquet files with typed columns, streaming rows so large exports stay out of memory.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// RowGroupSize is the number of rows buffered in memory before a row group is flushed to the output
+var RowGroupSize int64 = 128 * 1024
+
+// nullWhenZero lists fields the API reports as null, which the models decode to zero.
+// They are written as nullable columns holding null instead of zero.
+var nullWhenZero = map[string]bool{
+	"duration_sector_1": true,
+	"duration_sector_2": true,
+	"duration_sector_3": true,
+	"lap_duration":      true,
+	"i1_speed":          true,
+	"i2_speed":          true,
+	"st_speed":          true,
+	"pit_duration":      true,
+}
+
+// stringDates lists string fields the models keep as sent by the API that hold dates, such as the date of a
+// team radio message. They are parsed and written like time.Time fields.
+var stringDates = map[string]bool{
+	"date": true,
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// ParquetWriter streams values of a model such as openf1go.Lap or openf1go.CarData to a Parquet file.
+// Columns are named after the json tags of the model:
+// dates become timestamp columns, nullable fields become optional columns and slices become list columns.
+type ParquetWriter[T any] struct {
+	writer  *parquet.Writer // Underlying Parquet writer
+	rowType reflect.Type    // Struct type describing a Parquet row
+	columns []column        // Conversion of each model field into a row field
+}
+
+// column describes how a model field is converted into a row field
+type column struct {
+	field   int                                 // Index of the field in the model
+	convert func(v reflect.Value) reflect.Value // Converts the model field into the row field type
+}
+
+// NewParquetWriter creates a writer for the model type T. Close must be called to write the file footer.
+func NewParquetWriter[T any](w io.Writer) (*ParquetWriter[T], error) {
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	if model.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, model)
+	}
+
+	p := &ParquetWriter[T]{}
+	fields := []reflect.StructField{}
+
+	for i := 0; i < model.NumField(); i++ {
+		f := model.Field(i)
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+
+		typ, tag, convert, err := parquetColumn(name, f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		fields = append(fields, reflect.StructField{Name: f.Name, Type: typ, Tag: reflect.StructTag(`parquet:"` + tag + `"`)})
+		p.columns = append(p.columns, column{field: i, convert: convert})
+	}
+
+	p.rowType = reflect.StructOf(fields)
+	schema := parquet.SchemaOf(reflect.New(p.rowType).Elem().Interface())
+	p.writer = parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd), parquet.MaxRowsPerRowGroup(RowGroupSize))
+
+	return p, nil
+}
+
+// Write appends values to the file
+func (p *ParquetWriter[T]) Write(values ...T) error {
+	row := reflect.New(p.rowType).Elem()
+
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		for i, c := range p.columns {
+			row.Field(i).Set(c.convert(v.Field(c.field)))
+		}
+		if err := p.writer.Write(row.Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close flushes buffered rows and writes the file footer. It does not close the underlying writer.
+func (p *ParquetWriter[T]) Close() error {
+	return p.writer.Close()
+}
+
+// WriteParquet writes values of a model to w as a Parquet file
+func WriteParquet[T any](w io.Writer, values []T) error {
+	p, err := NewParquetWriter[T](w)
+	if err != nil {
+		return err
+	}
+	if err := p.Write(values...); err != nil {
+		return err
+	}
+	return p.Close()
+}
+
+// WriteParquetSeq writes every value of the sequence to w as a Parquet file, e.g. values read page by page
+func WriteParquetSeq[T any](w io.Writer, values iter.Seq[T]) error {
+	p, err := NewParquetWriter[T](w)
+	if err != nil {
+		return err
+	}
+	for value := range values {
+		if err := p.Write(value); err != nil {
+			return err
+		}
+	}
+	return p.Close()
+}
+
+// parquetColumn returns the row field type, parquet tag and conversion for a model field
+func parquetColumn(name string, t reflect.Type) (reflect.Type, string, func(reflect.Value) reflect.Value, error) {
+	nullable := nullWhenZero[name]
+
+	switch {
+	case t == timeType:
+		// Zero dates are missing dates
+		return reflect.TypeOf((*time.Time)(nil)), name + ",optional,timestamp(microsecond:utc)", func(v reflect.Value) reflect.Value {
+			tm := v.Interface().(time.Time)
+			if tm.IsZero() {
+				return reflect.Zero(reflect.TypeOf((*time.Time)(nil)))
+			}
+			utc := tm.UTC()
+			return reflect.ValueOf(&utc)
+		}, nil
+
+	case t.Kind() == reflect.String && stringDates[name]:
+		// Dates that do not parse are missing dates
+		return reflect.TypeOf((*time.Time)(nil)), name + ",optional,timestamp(microsecond:utc)", func(v reflect.Value) reflect.Value {
+			tm, ok := parseDate(v.String())
+			if !ok {
+				return reflect.Zero(reflect.TypeOf((*time.Time)(nil)))
+			}
+			return reflect.ValueOf(&tm)
+		}, nil
+
+	case t == rawMessageType:
+		// Raw values such as gaps are written as numbers, anything else such as "+1 LAP" is null
+		return reflect.TypeOf((*float64)(nil)), name + ",optional", func(v reflect.Value) reflect.Value {
+			var f float64
+			if err := json.Unmarshal(v.Bytes(), &f); err != nil || strings.TrimSpace(string(v.Bytes())) == "null" {
+				return reflect.Zero(reflect.TypeOf((*float64)(nil)))
+			}
+			return reflect.ValueOf(&f)
+		}, nil
+
+	case t.Kind() == reflect.Slice && isInt(t.Elem().Kind()):
+		return reflect.TypeOf([]int64{}), name + ",list", func(v reflect.Value) reflect.Value {
+			list := make([]int64, v.Len())
+			for i := range list {
+				list[i] = v.Index(i).Int()
+			}
+			return reflect.ValueOf(list)
+		}, nil
+
+	case isInt(t.Kind()) && nullable:
+		return reflect.TypeOf((*int64)(nil)), name + ",optional", func(v reflect.Value) reflect.Value {
+			if v.Int() == 0 {
+				return reflect.Zero(reflect.TypeOf((*int64)(nil)))
+			}
+			n := v.Int()
+			return reflect.ValueOf(&n)
+		}, nil
+
+	case isInt(t.Kind()):
+		return reflect.TypeOf(int64(0)), name, func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Int())
+		}, nil
+
+	case t.Kind() == reflect.Float64 && nullable:
+		return reflect.TypeOf((*float64)(nil)), name + ",optional", func(v reflect.Value) reflect.Value {
+			if v.Float() == 0 {
+				return reflect.Zero(reflect.TypeOf((*float64)(nil)))
+			}
+			f := v.Float()
+			return reflect.ValueOf(&f)
+		}, nil
+
+	case t.Kind() == reflect.Float64 || t.Kind() == reflect.Float32:
+		return reflect.TypeOf(float64(0)), name, func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Float())
+		}, nil
+
+	case t.Kind() == reflect.Bool:
+		return reflect.TypeOf(false), name, func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Bool())
+		}, nil
+
+	case t.Kind() == reflect.String:
+		return reflect.TypeOf(""), name, func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.String())
+		}, nil
+	}
+
+	return nil, "", nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+}
+
+// jsonName returns the name of a field in the API, or an empty string for fields the API does not return
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// isInt reports whether the kind is a signed integer
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// parseDate parses a date held in a string field, returning false when it is empty or not a date
+func parseDate(s string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// dateRow reads back the date column of an exported file
+type dateRow struct {
+	Date *time.Time `parquet:"date,optional,timestamp(microsecond:utc)"`
+}
+
+func TestWriteParquetTeamRadioDate(t *testing.T) {
+	tests := []struct {
+		name string
+		date string
+		want *time.Time
+	}{
+		{name: "utc", date: "2023-09-17T12:07:31.123000+00:00", want: ptr(time.Date(2023, 9, 17, 12, 7, 31, 123000000, time.UTC))},
+		{name: "other zone", date: "2023-09-17T20:07:31+08:00", want: ptr(time.Date(2023, 9, 17, 12, 7, 31, 0, time.UTC))},
+		{name: "missing", date: ""},
+		{name: "not a date", date: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteParquet(&buf, []openf1go.TeamRadio{{Date: tt.date, DriverNumber: 1}}); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			column, ok := f.Schema().Lookup("date")
+			if !ok || !strings.HasPrefix(column.Node.Type().LogicalType().String(), "TIMESTAMP") {
+				t.Fatalf("date column type = %v, want a timestamp", column.Node.Type())
+			}
+
+			rows, err := parquet.Read[dateRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rows[0].Date
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("date = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ptr returns a pointer to the value
+func ptr[T any](v T) *T {
+	return &v
+}
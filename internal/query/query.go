@@ -0,0 +1,181 @@
+package query
+
+// Parses OpenF1 query strings and matches decoded JSON records against them.
+// Shared by the fake server and the offline data sources so both honour the same semantics as the API.
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter represents a single condition of a query, e.g. driver_number=1 or date>=2023-09-16
+type Filter struct {
+	Field string // Name of the field the condition applies to
+	Op    string // One of "=", ">", ">=", "<" or "<="
+	Value string // Unescaped value to compare with
+}
+
+// Parse splits a raw query string into filters.
+// Operators may be written literally (date>=2023-09-16) or escaped inside the key (date%3E%3D=2023-09-16).
+func Parse(rawQuery string) ([]Filter, error) {
+	filters := []Filter{}
+
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+
+		// Find the first literal operator character
+		i := strings.IndexAny(part, "<>=")
+		if i < 0 {
+			continue
+		}
+
+		rawField, op, rawValue := part[:i], string(part[i]), part[i+1:]
+		if op != "=" && strings.HasPrefix(rawValue, "=") {
+			op += "="
+			rawValue = rawValue[1:]
+		}
+
+		field, err := url.QueryUnescape(rawField)
+		if err != nil {
+			return nil, err
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return nil, err
+		}
+
+		// An escaped operator at the end of the key replaces the "=" separator
+		if op == "=" {
+			for _, suffix := range []string{">=", "<=", ">", "<"} {
+				if strings.HasSuffix(field, suffix) {
+					field, op = strings.TrimSuffix(field, suffix), suffix
+					break
+				}
+			}
+		}
+
+		filters = append(filters, Filter{Field: field, Op: op, Value: value})
+	}
+
+	return filters, nil
+}
+
+// String formats the filter the way UrlBuilder does, e.g. date>=2023-09-16T13%3A03%3A35
+func (f Filter) String() string {
+	return url.QueryEscape(f.Field) + f.Op + url.QueryEscape(f.Value)
+}
+
+// Canonical returns the query with its filters sorted and escaped consistently,
+// so queries that differ only in ordering or escaping compare equal.
+func Canonical(rawQuery string) (string, error) {
+	filters, err := Parse(rawQuery)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = f.String()
+	}
+	sort.Strings(parts)
+
+	return strings.Join(parts, "&"), nil
+}
+
+// Match reports whether a decoded JSON record satisfies every filter.
+// Records missing a filtered field do not match.
+func Match(record map[string]any, filters []Filter) bool {
+	for _, f := range filters {
+		v, ok := record[f.Field]
+		if !ok || !f.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// match compares a single decoded JSON value with the filter
+func (f Filter) match(v any) bool {
+	switch value := v.(type) {
+	case float64:
+		want, err := strconv.ParseFloat(f.Value, 64)
+		if err != nil {
+			return false
+		}
+		return compare(cmpFloat(value, want), f.Op)
+	case bool:
+		want, err := strconv.ParseBool(f.Value)
+		if err != nil {
+			return false
+		}
+		return f.Op == "=" && value == want
+	case string:
+		// Dates are compared as instants so precision and time zone notation do not matter
+		if got, ok := parseTime(value); ok {
+			if want, ok := parseTime(f.Value); ok {
+				return compare(got.Compare(want), f.Op)
+			}
+		}
+		return compare(strings.Compare(value, f.Value), f.Op)
+	case nil:
+		return f.Op == "=" && (f.Value == "" || f.Value == "null")
+	}
+	return false
+}
+
+// compare applies the operator to the result of a three way comparison
+func compare(c int, op string) bool {
+	switch op {
+	case "=":
+		return c == 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// cmpFloat compares two numbers with a tolerance for values read back from text
+func cmpFloat(a, b float64) int {
+	switch {
+	case math.Abs(a-b) < 1e-9:
+		return 0
+	case a < b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// timeLayouts lists the date formats accepted by the API, values without a zone are read as UTC
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTime parses a date in any of the accepted layouts
+func parseTime(s string) (time.Time, bool) {
+	if len(s) < len("2006-01-02") || s[4] != '-' {
+		return time.Time{}, false
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawQuery string
+		want     []Filter
+	}{
+		{
+			name:     "equality",
+			rawQuery: "session_key=9158&driver_number=1",
+			want:     []Filter{{Field: "session_key", Op: "=", Value: "9158"}, {Field: "driver_number", Op: "=", Value: "1"}},
+		},
+		{
+			name:     "literal operators",
+			rawQuery: "date>=2023-09-17T12:00:00&date<2023-09-17T13%3A00%3A00&speed>300&lap_number<=5",
+			want: []Filter{
+				{Field: "date", Op: ">=", Value: "2023-09-17T12:00:00"},
+				{Field: "date", Op: "<", Value: "2023-09-17T13:00:00"},
+				{Field: "speed", Op: ">", Value: "300"},
+				{Field: "lap_number", Op: "<=", Value: "5"},
+			},
+		},
+		{
+			name:     "escaped operators",
+			rawQuery: "date%3E%3D=2023-09-17T12%3A00%3A00&speed%3E=300&lap_number%3C%3D=5&date%3C=2023-09-18",
+			want: []Filter{
+				{Field: "date", Op: ">=", Value: "2023-09-17T12:00:00"},
+				{Field: "speed", Op: ">", Value: "300"},
+				{Field: "lap_number", Op: "<=", Value: "5"},
+				{Field: "date", Op: "<", Value: "2023-09-18"},
+			},
+		},
+		{
+			name:     "escaped value",
+			rawQuery: "date>=2023-09-17T12%3A00%3A00%2B00%3A00&team_name=Red+Bull+Racing",
+			want: []Filter{
+				{Field: "date", Op: ">=", Value: "2023-09-17T12:00:00+00:00"},
+				{Field: "team_name", Op: "=", Value: "Red Bull Racing"},
+			},
+		},
+		{
+			name:     "empty parts and keys without a value are skipped",
+			rawQuery: "&session_key=latest&&csv",
+			want:     []Filter{{Field: "session_key", Op: "=", Value: "latest"}},
+		},
+		{
+			name: "empty query",
+			want: []Filter{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.rawQuery)
+			if err != nil {
+				t.Fatalf("Parse() returned %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse("date>=%zz"); err == nil {
+		t.Error("Parse() with an invalid escape returned no error")
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawQuery string
+		want     string
+	}{
+		{name: "sorted", rawQuery: "session_key=9158&driver_number=1", want: "driver_number=1&session_key=9158"},
+		{name: "literal operator", rawQuery: "date>=2023-09-17T12:00:00", want: "date>=2023-09-17T12%3A00%3A00"},
+		{name: "escaped operator", rawQuery: "date%3E%3D=2023-09-17T12%3A00%3A00", want: "date>=2023-09-17T12%3A00%3A00"},
+		{name: "empty", rawQuery: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonical(tt.rawQuery)
+			if err != nil {
+				t.Fatalf("Canonical() returned %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Canonical() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	record := map[string]any{
+		"date":          "2023-09-17T12:02:30.000000+00:00",
+		"driver_number": float64(1),
+		"speed":         float64(300),
+		"drs":           true,
+		"message":       "SAFETY CAR DEPLOYED",
+		"flag":          nil,
+	}
+
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    bool
+	}{
+		{name: "no filters", want: true},
+		{name: "number equal", filters: []Filter{{Field: "driver_number", Op: "=", Value: "1"}}, want: true},
+		{name: "number different", filters: []Filter{{Field: "driver_number", Op: "=", Value: "44"}}, want: false},
+		{name: "number range", filters: []Filter{{Field: "speed", Op: ">=", Value: "300"}, {Field: "speed", Op: "<", Value: "301"}}, want: true},
+		{name: "number above", filters: []Filter{{Field: "speed", Op: ">", Value: "300"}}, want: false},
+		{name: "number compared with text", filters: []Filter{{Field: "speed", Op: "=", Value: "fast"}}, want: false},
+		{name: "bool", filters: []Filter{{Field: "drs", Op: "=", Value: "true"}}, want: true},
+		{name: "bool with comparison", filters: []Filter{{Field: "drs", Op: ">", Value: "false"}}, want: false},
+		{name: "date in another zone", filters: []Filter{{Field: "date", Op: "=", Value: "2023-09-17T20:02:30+08:00"}}, want: true},
+		{name: "date without a zone", filters: []Filter{{Field: "date", Op: ">", Value: "2023-09-17T12:02:29.5"}}, want: true},
+		{name: "date day", filters: []Filter{{Field: "date", Op: "<", Value: "2023-09-17"}}, want: false},
+		{name: "text", filters: []Filter{{Field: "message", Op: "=", Value: "SAFETY CAR DEPLOYED"}}, want: true},
+		{name: "null", filters: []Filter{{Field: "flag", Op: "=", Value: "null"}}, want: true},
+		{name: "missing field", filters: []Filter{{Field: "lap_number", Op: "=", Value: "1"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(record, tt.filters); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
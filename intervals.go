@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -42,7 +43,7 @@ func (c *Client) GetIntervals(interval Interval) (IntervalsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +67,7 @@ func (c *Client) GetAllDriversCurrentIntervals() (IntervalsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +100,7 @@ func (c *Client) GetDriverCurrentIntervals(driver Driver) (IntervalsResponse, er
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -111,3 +112,67 @@ func (c *Client) GetDriverCurrentIntervals(driver Driver) (IntervalsResponse, er
 
 	return intervalsResponse, nil
 }
+
+// Gap represents a parsed gap value from the intervals endpoint.
+// The API reports gaps either as a number of seconds, as a string such as "+1 LAP", or as null.
+type Gap struct {
+	Seconds float64 // Gap in seconds when the gap is time based
+	Laps    int     // Number of laps when the gap is lap based
+	Valid   bool    // Indicates if the API provided a gap at all
+}
+
+// String formats the gap the way it is shown on a timing screen
+func (g Gap) String() string {
+	switch {
+	case !g.Valid:
+		return ""
+	case g.Laps == 1:
+		return "+1 LAP"
+	case g.Laps > 1:
+		return "+" + strconv.Itoa(g.Laps) + " LAPS"
+	default:
+		return "+" + strconv.FormatFloat(g.Seconds, 'f', 3, 64)
+	}
+}
+
+// parseGap converts a raw JSON gap value into a Gap
+func parseGap(raw json.RawMessage) Gap {
+	// Null or missing values mean the API has no gap for this record
+	if len(raw) == 0 || string(raw) == "null" {
+		return Gap{}
+	}
+
+	// Time based gaps are plain numbers
+	var seconds float64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return Gap{Seconds: seconds, Valid: true}
+	}
+
+	// Lap based gaps are strings such as "+1 LAP" or "+2 LAPS"
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return Gap{}
+	}
+	fields := strings.Fields(strings.TrimPrefix(s, "+"))
+	if len(fields) == 0 {
+		return Gap{}
+	}
+	if laps, err := strconv.Atoi(fields[0]); err == nil {
+		return Gap{Laps: laps, Valid: true}
+	}
+	if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil {
+		return Gap{Seconds: seconds, Valid: true}
+	}
+
+	return Gap{}
+}
+
+// GapToLeaderValue returns the parsed gap to the race leader
+func (i Interval) GapToLeaderValue() Gap {
+	return parseGap(i.GapToLeader)
+}
+
+// IntervalValue returns the parsed interval to the car ahead
+func (i Interval) IntervalValue() Gap {
+	return parseGap(i.Interval)
+}
@@ -28,9 +28,9 @@ type Lap struct {
 	LapDuration     float64   `json:"lap_duration"`      // Total lap duration in seconds
 	LapNumber       int       `json:"lap_number"`        // Lap number in the session
 	MeetingKey      int       `json:"meeting_key"`       // Unique identifier for the meeting
-	SegmentsSector1 []int     `json:"segments_sector_1"` // Segment times for sector 1
-	SegmentsSector2 []int     `json:"segments_sector_2"` // Segment times for sector 2
-	SegmentsSector3 []int     `json:"segments_sector_3"` // Segment times for sector 3
+	SegmentsSector1 []int     `json:"segments_sector_1"` // Mini-sector segment codes for sector 1, see Segment
+	SegmentsSector2 []int     `json:"segments_sector_2"` // Mini-sector segment codes for sector 2, see Segment
+	SegmentsSector3 []int     `json:"segments_sector_3"` // Mini-sector segment codes for sector 3, see Segment
 	SessionKey      int       `json:"session_key"`       // Unique identifier for the session
 	StSpeed         int       `json:"st_speed"`          // Speed at the start/finish line
 }
@@ -51,7 +51,7 @@ func (c *Client) GetLaps(lap Lap) (LapsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +84,7 @@ func (c *Client) GetLatestLapsByDriver(driver Driver) (LapsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +108,7 @@ func (c *Client) GetLatestLaps() (LapsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,292 @@
+package openf1go
+
+// Assembles the latest position, interval, lap, stint and pit feeds into a single timing tower.
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TimingTower represents the timing tower ordered by position
+type TimingTower []TimingRow
+
+// TimingRow represents a single driver's line on the timing tower
+type TimingRow struct {
+	Position     int       // Current position of the driver
+	DriverNumber int       // Driver's unique number
+	NameAcronym  string    // Acronym of the driver's name
+	TeamName     string    // Name of the team
+	TeamColour   string    // Team's color
+	LapNumber    int       // Lap the driver is currently on
+	LastLap      float64   // Duration of the last completed lap in seconds
+	BestLap      float64   // Duration of the best completed lap in seconds
+	GapToLeader  Gap       // Gap to the race leader
+	Interval     Gap       // Interval to the car ahead
+	Compound     string    // Tyre compound currently fitted
+	TyreAge      int       // Age of the current tyres in laps
+	PitCount     int       // Number of pit stops made
+	Updated      time.Time // Time of the most recent update for the driver
+}
+
+// LiveSession keeps the current state of a session and is safe for concurrent use
+type LiveSession struct {
+	mu      sync.RWMutex
+	drivers map[int]*liveDriver // State per driver number
+}
+
+// liveDriver holds everything known about a single driver in the session
+type liveDriver struct {
+	driver   Driver
+	position Position
+	interval Interval
+	laps     map[int]Lap   // Laps keyed by lap number
+	stints   map[int]Stint // Stints keyed by stint number
+	pits     map[int]Pit   // Pit stops keyed by lap number
+}
+
+// NewLiveSession creates and returns an empty LiveSession
+func NewLiveSession() *LiveSession {
+	return &LiveSession{drivers: map[int]*liveDriver{}}
+}
+
+// driver returns the state for a driver number, creating it when needed.
+// The caller must hold the write lock.
+func (s *LiveSession) driver(number int) *liveDriver {
+	d, ok := s.drivers[number]
+	if !ok {
+		d = &liveDriver{
+			driver: Driver{DriverNumber: number},
+			laps:   map[int]Lap{},
+			stints: map[int]Stint{},
+			pits:   map[int]Pit{},
+		}
+		s.drivers[number] = d
+	}
+	return d
+}
+
+// IngestDrivers stores driver details used to label the timing tower
+func (s *LiveSession) IngestDrivers(drivers DriversResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestDrivers(drivers)
+}
+
+// ingestDrivers is IngestDrivers for callers holding the write lock
+func (s *LiveSession) ingestDrivers(drivers DriversResponse) {
+	for _, driver := range drivers {
+		s.driver(driver.DriverNumber).driver = driver
+	}
+}
+
+// IngestPositions keeps the most recent position record for each driver
+func (s *LiveSession) IngestPositions(positions PostionsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestPositions(positions)
+}
+
+// ingestPositions is IngestPositions for callers holding the write lock
+func (s *LiveSession) ingestPositions(positions PostionsResponse) {
+	for _, position := range positions {
+		d := s.driver(position.DriverNumber)
+		if !position.Date.Before(d.position.Date) {
+			d.position = position
+		}
+	}
+}
+
+// IngestIntervals keeps the most recent interval record for each driver
+func (s *LiveSession) IngestIntervals(intervals IntervalsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestIntervals(intervals)
+}
+
+// ingestIntervals is IngestIntervals for callers holding the write lock
+func (s *LiveSession) ingestIntervals(intervals IntervalsResponse) {
+	for _, interval := range intervals {
+		d := s.driver(interval.DriverNumber)
+		if !interval.Date.Before(d.interval.Date) {
+			d.interval = interval
+		}
+	}
+}
+
+// IngestLaps stores laps for each driver, replacing laps that were updated by the API
+func (s *LiveSession) IngestLaps(laps LapsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestLaps(laps)
+}
+
+// ingestLaps is IngestLaps for callers holding the write lock
+func (s *LiveSession) ingestLaps(laps LapsResponse) {
+	for _, lap := range laps {
+		s.driver(lap.DriverNumber).laps[lap.LapNumber] = lap
+	}
+}
+
+// IngestStints stores stints for each driver, replacing stints that were updated by the API
+func (s *LiveSession) IngestStints(stints StintsReponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestStints(stints)
+}
+
+// ingestStints is IngestStints for callers holding the write lock
+func (s *LiveSession) ingestStints(stints StintsReponse) {
+	for _, stint := range stints {
+		s.driver(stint.DriverNumber).stints[stint.StintNumber] = stint
+	}
+}
+
+// IngestPits stores pit stops for each driver
+func (s *LiveSession) IngestPits(pits PitResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestPits(pits)
+}
+
+// ingestPits is IngestPits for callers holding the write lock
+func (s *LiveSession) ingestPits(pits PitResponse) {
+	for _, pit := range pits {
+		s.driver(pit.DriverNumber).pits[pit.LapNumber] = pit
+	}
+}
+
+// Refresh fetches the latest drivers, positions, intervals, laps, stints and pits and ingests them together,
+// so a Snapshot never mixes feeds from before and after the refresh.
+// The first error encountered is returned, no further feeds are fetched and nothing is ingested.
+func (s *LiveSession) Refresh(c *Client) error {
+	drivers, err := c.GetLatestDrivers()
+	if err != nil {
+		return err
+	}
+
+	positions, err := c.GetAllDriversLatestPositions()
+	if err != nil {
+		return err
+	}
+
+	intervals, err := c.GetAllDriversCurrentIntervals()
+	if err != nil {
+		return err
+	}
+
+	laps, err := c.GetLatestLaps()
+	if err != nil {
+		return err
+	}
+
+	stints, err := c.GetAllDriversLatestStints()
+	if err != nil {
+		return err
+	}
+
+	pits, err := c.GetAllDriversLatestPits()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ingestDrivers(drivers)
+	s.ingestPositions(positions)
+	s.ingestIntervals(intervals)
+	s.ingestLaps(laps)
+	s.ingestStints(stints)
+	s.ingestPits(pits)
+
+	return nil
+}
+
+// Snapshot returns a consistent copy of the timing tower ordered by position.
+// Drivers without a known position are placed at the end ordered by driver number.
+func (s *LiveSession) Snapshot() TimingTower {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tower := make(TimingTower, 0, len(s.drivers))
+	for _, d := range s.drivers {
+		tower = append(tower, d.row())
+	}
+
+	sort.Slice(tower, func(i, j int) bool {
+		a, b := tower[i], tower[j]
+		if (a.Position == 0) != (b.Position == 0) {
+			return b.Position == 0
+		}
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return a.DriverNumber < b.DriverNumber
+	})
+
+	return tower
+}
+
+// row builds the timing tower row for a driver.
+// The caller must hold at least the read lock.
+func (d *liveDriver) row() TimingRow {
+	row := TimingRow{
+		Position:     d.position.Position,
+		DriverNumber: d.driver.DriverNumber,
+		NameAcronym:  d.driver.NameAcronym,
+		TeamName:     d.driver.TeamName,
+		TeamColour:   d.driver.TeamColour,
+		GapToLeader:  d.interval.GapToLeaderValue(),
+		Interval:     d.interval.IntervalValue(),
+		PitCount:     len(d.pits),
+		Updated:      d.position.Date,
+	}
+	if d.interval.Date.After(row.Updated) {
+		row.Updated = d.interval.Date
+	}
+
+	// Find the current lap, the last completed lap and the best completed lap
+	lastCompleted := 0
+	for number, lap := range d.laps {
+		if number > row.LapNumber {
+			row.LapNumber = number
+		}
+		if lap.DateStart.After(row.Updated) {
+			row.Updated = lap.DateStart
+		}
+		if lap.LapDuration <= 0 {
+			continue
+		}
+		if number > lastCompleted {
+			lastCompleted = number
+			row.LastLap = lap.LapDuration
+		}
+		if row.BestLap == 0 || lap.LapDuration < row.BestLap {
+			row.BestLap = lap.LapDuration
+		}
+	}
+
+	// The current tyres belong to the stint with the highest stint number
+	var current Stint
+	for number, stint := range d.stints {
+		if number > current.StintNumber {
+			current = stint
+		}
+	}
+	if current.StintNumber > 0 {
+		row.Compound = current.Compound
+		row.TyreAge = current.TyreAgeAtStart
+		if row.LapNumber > current.LapStart {
+			row.TyreAge += row.LapNumber - current.LapStart
+		}
+	}
+
+	return row
+}
@@ -0,0 +1,201 @@
+package openf1go_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+func TestLiveSessionSnapshot(t *testing.T) {
+	start := time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	tests := []struct {
+		name   string
+		ingest func(s *openf1go.LiveSession)
+		want   openf1go.TimingTower
+	}{
+		{
+			name: "ordered by position",
+			ingest: func(s *openf1go.LiveSession) {
+				s.IngestDrivers(openf1go.DriversResponse{
+					{DriverNumber: 1, NameAcronym: "VER", TeamName: "Red Bull Racing", TeamColour: "3671C6"},
+					{DriverNumber: 44, NameAcronym: "HAM", TeamName: "Mercedes", TeamColour: "6CD3BF"},
+				})
+				s.IngestPositions(openf1go.PostionsResponse{
+					{DriverNumber: 1, Position: 1, Date: at(0)},
+					{DriverNumber: 44, Position: 2, Date: at(0)},
+				})
+			},
+			want: openf1go.TimingTower{
+				{Position: 1, DriverNumber: 1, NameAcronym: "VER", TeamName: "Red Bull Racing", TeamColour: "3671C6", Updated: at(0)},
+				{Position: 2, DriverNumber: 44, NameAcronym: "HAM", TeamName: "Mercedes", TeamColour: "6CD3BF", Updated: at(0)},
+			},
+		},
+		{
+			name: "latest position wins",
+			ingest: func(s *openf1go.LiveSession) {
+				s.IngestPositions(openf1go.PostionsResponse{
+					{DriverNumber: 1, Position: 2, Date: at(10)},
+					{DriverNumber: 44, Position: 1, Date: at(10)},
+				})
+				s.IngestPositions(openf1go.PostionsResponse{
+					{DriverNumber: 1, Position: 1, Date: at(0)}, // Older, ignored
+				})
+			},
+			want: openf1go.TimingTower{
+				{Position: 1, DriverNumber: 44, Updated: at(10)},
+				{Position: 2, DriverNumber: 1, Updated: at(10)},
+			},
+		},
+		{
+			name: "drivers without a position last",
+			ingest: func(s *openf1go.LiveSession) {
+				s.IngestDrivers(openf1go.DriversResponse{{DriverNumber: 63}, {DriverNumber: 4}})
+				s.IngestPositions(openf1go.PostionsResponse{{DriverNumber: 44, Position: 1, Date: at(0)}})
+			},
+			want: openf1go.TimingTower{
+				{Position: 1, DriverNumber: 44, Updated: at(0)},
+				{DriverNumber: 4},
+				{DriverNumber: 63},
+			},
+		},
+		{
+			name: "time and lapped gaps",
+			ingest: func(s *openf1go.LiveSession) {
+				s.IngestPositions(openf1go.PostionsResponse{
+					{DriverNumber: 1, Position: 1, Date: at(0)},
+					{DriverNumber: 44, Position: 2, Date: at(0)},
+					{DriverNumber: 2, Position: 3, Date: at(0)},
+				})
+				s.IngestIntervals(openf1go.IntervalsResponse{
+					{DriverNumber: 1, GapToLeader: json.RawMessage(`0`), Interval: json.RawMessage(`null`), Date: at(5)},
+					{DriverNumber: 44, GapToLeader: json.RawMessage(`1.5`), Interval: json.RawMessage(`1.5`), Date: at(5)},
+					{DriverNumber: 2, GapToLeader: json.RawMessage(`"+2 LAPS"`), Interval: json.RawMessage(`"+1 LAP"`), Date: at(5)},
+					{DriverNumber: 44, GapToLeader: json.RawMessage(`9.9`), Interval: json.RawMessage(`9.9`), Date: at(1)}, // Older, ignored
+				})
+			},
+			want: openf1go.TimingTower{
+				{Position: 1, DriverNumber: 1, GapToLeader: openf1go.Gap{Valid: true}, Updated: at(5)},
+				{Position: 2, DriverNumber: 44, GapToLeader: openf1go.Gap{Seconds: 1.5, Valid: true}, Interval: openf1go.Gap{Seconds: 1.5, Valid: true}, Updated: at(5)},
+				{Position: 3, DriverNumber: 2, GapToLeader: openf1go.Gap{Laps: 2, Valid: true}, Interval: openf1go.Gap{Laps: 1, Valid: true}, Updated: at(5)},
+			},
+		},
+		{
+			name: "laps, tyres and pit stops",
+			ingest: func(s *openf1go.LiveSession) {
+				s.IngestPositions(openf1go.PostionsResponse{{DriverNumber: 1, Position: 1, Date: at(0)}})
+				s.IngestLaps(openf1go.LapsResponse{
+					{DriverNumber: 1, LapNumber: 1},
+					{DriverNumber: 1, LapNumber: 2, DateStart: at(100), LapDuration: 95.5},
+					{DriverNumber: 1, LapNumber: 3, DateStart: at(196), LapDuration: 97.25},
+					{DriverNumber: 1, LapNumber: 4, DateStart: at(293)},
+				})
+				// The API completes lap 4 later on
+				s.IngestLaps(openf1go.LapsResponse{{DriverNumber: 1, LapNumber: 4, DateStart: at(293), LapDuration: 96}})
+				s.IngestLaps(openf1go.LapsResponse{{DriverNumber: 1, LapNumber: 5, DateStart: at(389)}})
+				s.IngestStints(openf1go.StintsReponse{
+					{DriverNumber: 1, StintNumber: 2, Compound: "HARD", LapStart: 3, TyreAgeAtStart: 2},
+					{DriverNumber: 1, StintNumber: 1, Compound: "MEDIUM", LapStart: 1, LapEnd: 2},
+				})
+				s.IngestPits(openf1go.PitResponse{{DriverNumber: 1, LapNumber: 2}})
+			},
+			want: openf1go.TimingTower{
+				{Position: 1, DriverNumber: 1, LapNumber: 5, LastLap: 96, BestLap: 95.5, Compound: "HARD", TyreAge: 4, PitCount: 1, Updated: at(389)},
+			},
+		},
+		{
+			name: "new tyres on their first lap",
+			ingest: func(s *openf1go.LiveSession) {
+				s.IngestLaps(openf1go.LapsResponse{{DriverNumber: 1, LapNumber: 3, DateStart: at(200)}})
+				s.IngestStints(openf1go.StintsReponse{{DriverNumber: 1, StintNumber: 2, Compound: "SOFT", LapStart: 3}})
+			},
+			want: openf1go.TimingTower{
+				{DriverNumber: 1, LapNumber: 3, Compound: "SOFT", Updated: at(200)},
+			},
+		},
+		{
+			name:   "empty",
+			ingest: func(s *openf1go.LiveSession) {},
+			want:   openf1go.TimingTower{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openf1go.NewLiveSession()
+			tt.ingest(s)
+
+			got := s.Snapshot()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Snapshot() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("row %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// snapshotTransport takes a snapshot of a live session before sending a request to the given endpoint
+type snapshotTransport struct {
+	session  *openf1go.LiveSession
+	endpoint string
+
+	mu       sync.Mutex
+	snapshot openf1go.TimingTower // Snapshot taken before the request
+}
+
+func (s *snapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/"+s.endpoint) {
+		s.mu.Lock()
+		s.snapshot = s.session.Snapshot()
+		s.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestLiveSessionRefresh(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+
+	session := openf1go.NewLiveSession()
+	transport := &snapshotTransport{session: session, endpoint: "pit"}
+	client := server.Client(openf1go.WithTransport(transport))
+
+	if err := session.Refresh(client); err != nil {
+		t.Fatalf("Refresh() returned %v", err)
+	}
+
+	// Nothing is ingested until every feed is fetched
+	if len(transport.snapshot) != 0 {
+		t.Errorf("Snapshot() during Refresh() = %+v, want an empty tower", transport.snapshot)
+	}
+
+	tower := session.Snapshot()
+	if len(tower) != 3 {
+		t.Fatalf("Snapshot() after Refresh() returned %d rows, want 3", len(tower))
+	}
+	for i, row := range tower {
+		if row.Position != i+1 || row.NameAcronym == "" || row.LapNumber == 0 || row.Compound == "" {
+			t.Errorf("row %d = %+v, want a complete row", i, row)
+		}
+	}
+
+	// A failed refresh leaves the tower as it was
+	server.FailNext(http.StatusServiceUnavailable, 1)
+	if err := session.Refresh(client); err == nil {
+		t.Error("Refresh() with a failing API returned no error")
+	}
+	if got := session.Snapshot(); len(got) != len(tower) || got[0] != tower[0] {
+		t.Errorf("Snapshot() after a failed Refresh() = %+v, want %+v", got, tower)
+	}
+}
@@ -42,7 +42,7 @@ func (c *Client) GetLocations(location Location) (LocationResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func (c *Client) GetAllDriversLatestLocations() (LocationResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +99,35 @@ func (c *Client) GetDriverLatestLocation(driver Driver) (LocationResponse, error
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response into the LocationResponse struct
+	if err := json.Unmarshal(resp, &locationResponse); err != nil {
+		return nil, err
+	}
+
+	return locationResponse, nil
+}
+
+// GetLocationsBetween fetches location data matching the Location filter with a date within [from, to)
+func (c *Client) GetLocationsBetween(location Location, from, to time.Time) (LocationResponse, error) {
+	var locationResponse LocationResponse
+
+	// Build the query arguments with the filter and the date range
+	args := buildArgs(location)
+	args = append(args, Arg{Key: "date>=", Value: from.Format(time.RFC3339Nano)}, Arg{Key: "date<", Value: to.Format(time.RFC3339Nano)})
+
+	// Build the URL with the query arguments
+	url, err := UrlBuilder(c.getLocationURL(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make the HTTP GET request
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +45,7 @@ func (c *Client) GetMeetings(meeting Meeting) (MeetingResponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func (c *Client) GetLatestMeeting() (Meeting, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return Meeting{}, err
 	}
@@ -0,0 +1,210 @@
+package openf1test
+
+// Provides an HTTP transport that records API interactions to a cassette file and replays them in later runs.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/stephenhoran/open-f1-go/internal/query"
+)
+
+// ErrInteractionNotFound is returned in replay mode for requests missing from the cassette
+var ErrInteractionNotFound = errors.New("interaction not found in cassette")
+
+// Mode controls whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	ModeReplay         Mode = iota // Serve interactions from the cassette, failing on unknown requests
+	ModeRecord                     // Send every request upstream and record the interaction, replacing any earlier one
+	ModeReplayOrRecord             // Serve known interactions and record unknown ones
+)
+
+// Interaction represents a recorded request and its response
+type Interaction struct {
+	URL         string `json:"url"`          // Normalised request URL, see NormalizeURL
+	Status      int    `json:"status"`       // Response status code
+	ContentType string `json:"content_type"` // Response content type
+	Body        string `json:"body"`         // Response body
+}
+
+// cassette is the file format of a cassette
+type cassette struct {
+	Interactions []Interaction `json:"interactions"` // Interactions in the order they were first recorded
+}
+
+// Recorder is an http.RoundTripper recording interactions to or replaying them from a cassette file.
+// Plug it into a client with openf1go.WithTransport and call Save once done to write recorded interactions.
+// Only 2xx responses are recorded, failures such as rate limits are passed on without replacing a recorded interaction.
+type Recorder struct {
+	path string // Path of the cassette file
+	mode Mode   // Whether to record or replay
+
+	mu           sync.Mutex
+	next         http.RoundTripper // Transport used to send requests upstream when recording
+	interactions []Interaction     // Interactions in recording order
+	index        map[string]int    // Position of each interaction by normalised URL
+	recorded     bool              // Whether interactions were recorded since the cassette was loaded or saved
+}
+
+// NewRecorder creates a recorder using the cassette at the given path, loading it when it exists.
+// Requests are sent upstream with http.DefaultTransport when recording.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, next: http.DefaultTransport, index: map[string]int{}}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && mode != ModeReplay:
+		return r, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+	for _, interaction := range c.Interactions {
+		r.add(interaction)
+	}
+
+	return r, nil
+}
+
+// SetTransport changes the transport used to send requests upstream when recording
+func (r *Recorder) SetTransport(next http.RoundTripper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.next = next
+}
+
+// Interactions returns the interactions held by the recorder
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction{}, r.interactions...)
+}
+
+// RoundTrip serves the request from the cassette or sends it upstream and records it, depending on the mode
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := NormalizeURL(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	i, ok := r.index[key]
+	var interaction Interaction
+	if ok {
+		interaction = r.interactions[i]
+	}
+	next := r.next
+	r.mu.Unlock()
+
+	if r.mode != ModeRecord {
+		if ok {
+			return interaction.response(req), nil
+		}
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s", ErrInteractionNotFound, key)
+		}
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction = Interaction{URL: key, Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Body: string(body)}
+
+	// Transient failures would replay as failures in every later run
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return interaction.response(req), nil
+	}
+
+	r.mu.Lock()
+	r.add(interaction)
+	r.recorded = true
+	r.mu.Unlock()
+
+	return interaction.response(req), nil
+}
+
+// add stores an interaction, replacing an earlier one for the same URL
+func (r *Recorder) add(interaction Interaction) {
+	if i, ok := r.index[interaction.URL]; ok {
+		r.interactions[i] = interaction
+		return
+	}
+	r.index[interaction.URL] = len(r.interactions)
+	r.interactions = append(r.interactions, interaction)
+}
+
+// Save writes the cassette file when interactions were recorded since it was loaded or last saved
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recorded {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return err
+	}
+
+	r.recorded = false
+	return nil
+}
+
+// response builds the HTTP response of a recorded interaction
+func (i Interaction) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	if i.ContentType != "" {
+		header.Set("Content-Type", i.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(i.Body))),
+		ContentLength: int64(len(i.Body)),
+		Request:       req,
+	}
+}
+
+// NormalizeURL returns the key an interaction is stored under: the request path followed by its query filters
+// sorted and escaped consistently. The host is left out so cassettes replay against any base URL.
+func NormalizeURL(req *http.Request) (string, error) {
+	q, err := query.Canonical(req.URL.RawQuery)
+	if err != nil {
+		return "", err
+	}
+	if q == "" {
+		return req.URL.Path, nil
+	}
+	return req.URL.Path + "?" + q, nil
+}
@@ -0,0 +1,144 @@
+package openf1test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "no query", url: "https://api.openf1.org/v1/drivers", want: "/v1/drivers"},
+		{name: "sorted filters", url: "https://api.openf1.org/v1/laps?session_key=9158&driver_number=1", want: "/v1/laps?driver_number=1&session_key=9158"},
+		{name: "escaped operator", url: "http://127.0.0.1:1234/v1/laps?lap_number%3E%3D=2", want: "/v1/laps?lap_number>=2"},
+		{name: "literal operator", url: "http://127.0.0.1:1234/v1/laps?lap_number>=2", want: "/v1/laps?lap_number>=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := NormalizeURL(req)
+			if err != nil {
+				t.Fatalf("NormalizeURL() returned %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	s := NewServer()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	args := []openf1go.Arg{{Key: "session_key", Value: "9158"}, {Key: "driver_number", Value: "1"}}
+
+	// Record a request and save the cassette
+	recorder, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := s.Client(openf1go.WithTransport(recorder)).Raw(context.Background(), "laps", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("cassette written before Save, Stat returned %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// Replay it against another base URL with the filters in another order
+	replayer, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := openf1go.New(openf1go.WithBaseURL("http://127.0.0.1:1/v1"), openf1go.WithTransport(replayer))
+
+	tests := []struct {
+		name     string
+		endpoint string
+		args     []openf1go.Arg
+		err      error
+	}{
+		{name: "recorded", endpoint: "laps", args: []openf1go.Arg{args[1], args[0]}},
+		{name: "not recorded", endpoint: "laps", args: args[:1], err: ErrInteractionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := client.Raw(context.Background(), tt.endpoint, tt.args)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Raw() returned %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && string(body) != string(recorded) {
+				t.Errorf("Raw() = %s, want %s", body, recorded)
+			}
+		})
+	}
+
+	if got := len(replayer.Interactions()); got != 1 {
+		t.Errorf("Interactions() returned %d interactions, want 1", got)
+	}
+
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("NewRecorder() in replay mode without a cassette returned no error")
+	}
+}
+
+func TestRecorderFailures(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	args := []openf1go.Arg{{Key: "session_key", Value: "9158"}}
+
+	tests := []struct {
+		name   string
+		status int
+		err    error
+	}{
+		{name: "rate limited", status: http.StatusTooManyRequests, err: openf1go.ErrRateLimited},
+		{name: "server error", status: http.StatusServiceUnavailable, err: openf1go.ErrUnexpectedStatus},
+		{name: "client error", status: http.StatusUnprocessableEntity, err: openf1go.ErrUnexpectedStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cassette.json")
+			recorder, err := NewRecorder(path, ModeReplayOrRecord)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := s.Client(openf1go.WithTransport(recorder))
+
+			// The failure is passed on but not recorded, so the next request goes upstream again
+			s.FailNext(tt.status, 1)
+			if _, err := client.Raw(context.Background(), "drivers", args); !errors.Is(err, tt.err) {
+				t.Fatalf("Raw() returned %v, want %v", err, tt.err)
+			}
+			if got := len(recorder.Interactions()); got != 0 {
+				t.Fatalf("recorded %d interactions after a %d response", got, tt.status)
+			}
+
+			if _, err := client.Raw(context.Background(), "drivers", args); err != nil {
+				t.Fatalf("Raw() after the failure returned %v", err)
+			}
+			if got := recorder.Interactions(); len(got) != 1 || got[0].Status != http.StatusOK {
+				t.Errorf("Interactions() = %+v, want the successful response", got)
+			}
+		})
+	}
+}
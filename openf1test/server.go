@@ -0,0 +1,232 @@
+package openf1test
+
+// Provides an OpenF1 compatible HTTP server serving fixture data for offline tests.
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/internal/query"
+)
+
+// Endpoints lists the endpoints served by the fake server
+var Endpoints = []string{
+	"car_data", "drivers", "intervals", "laps", "location", "meetings",
+	"pit", "position", "race_control", "sessions", "stints", "team_radio", "weather",
+}
+
+//go:embed testdata/*.json
+var defaultFixtures embed.FS
+
+// Fixtures returns the embedded fixture set: one race session with three drivers and a few laps
+func Fixtures() fs.FS {
+	fixtures, _ := fs.Sub(defaultFixtures, "testdata")
+	return fixtures
+}
+
+// Server is an OpenF1 compatible HTTP server backed by fixture records
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	records  map[string][]map[string]any // Decoded records per endpoint in fixture order
+	latency  time.Duration               // Delay added before every response
+	faults   []int                       // Status codes returned by the next requests, in order
+	requests int                         // Number of requests received
+}
+
+// NewServer starts a server serving the embedded fixtures. Call Close when done.
+func NewServer() *Server {
+	s, err := NewServerWithFixtures(Fixtures())
+	if err != nil {
+		panic("openf1test: embedded fixtures are invalid: " + err.Error())
+	}
+	return s
+}
+
+// NewServerWithFixtures starts a server serving the fixtures of a directory holding one <endpoint>.json file
+// per endpoint, each containing a JSON array of records. Missing endpoints serve empty arrays.
+func NewServerWithFixtures(fixtures fs.FS) (*Server, error) {
+	s := &Server{records: map[string][]map[string]any{}}
+
+	for _, endpoint := range Endpoints {
+		data, err := fs.ReadFile(fixtures, endpoint+".json")
+		if err != nil {
+			continue
+		}
+		var records []map[string]any
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, err
+		}
+		s.records[endpoint] = records
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s, nil
+}
+
+// Client returns a client pointed at the server
+func (s *Server) Client(opts ...openf1go.Option) *openf1go.Client {
+	return openf1go.New(append([]openf1go.Option{openf1go.WithBaseURL(s.BaseURL())}, opts...)...)
+}
+
+// BaseURL returns the base URL to configure clients with, e.g. http://127.0.0.1:1234/v1
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// AddRecords appends records to an endpoint. Records are encoded to JSON the same way the client decodes them.
+func (s *Server) AddRecords(endpoint string, records ...any) error {
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		var record map[string]any
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.records[endpoint] = append(s.records[endpoint], record)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// SetLatency delays every response by the given duration
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// FailNext makes the next count requests fail with the given status, e.g. 429 or 503
+func (s *Server) FailNext(status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		s.faults = append(s.faults, status)
+	}
+}
+
+// Requests returns the number of requests received
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// handle serves /v1/<endpoint> requests
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	latency := s.latency
+	fault := 0
+	if len(s.faults) > 0 {
+		fault, s.faults = s.faults[0], s.faults[1:]
+	}
+	s.mu.Unlock()
+
+	// Simulate a slow API, giving up when the client does
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if fault != 0 {
+		if fault == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeError(w, fault, http.StatusText(fault))
+		return
+	}
+
+	endpoint := path.Base(r.URL.Path)
+	if !strings.HasPrefix(r.URL.Path, "/v1/") || !isEndpoint(endpoint) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	filters, err := query.Parse(r.URL.RawQuery)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	matched := filter(s.records, endpoint, filters)
+	s.mu.Unlock()
+
+	_ = json.NewEncoder(w).Encode(matched)
+}
+
+// filter returns the records of an endpoint matching the filters.
+// "latest" meeting and session keys resolve to the highest keys found in the sessions, or in the endpoint itself.
+func filter(records map[string][]map[string]any, endpoint string, filters []query.Filter) []map[string]any {
+	resolved := make([]query.Filter, len(filters))
+	for i, f := range filters {
+		if f.Value == "latest" && (f.Field == "meeting_key" || f.Field == "session_key") {
+			f.Value = strconv.FormatFloat(latest(records, endpoint, f.Field), 'f', -1, 64)
+		}
+		resolved[i] = f
+	}
+
+	matched := []map[string]any{}
+	for _, record := range records[endpoint] {
+		if query.Match(record, resolved) {
+			matched = append(matched, record)
+		}
+	}
+
+	return matched
+}
+
+// latest returns the highest value of a key field, preferring the sessions and meetings endpoints
+func latest(records map[string][]map[string]any, endpoint, field string) float64 {
+	highest := 0.0
+	for _, source := range []string{"sessions", "meetings", endpoint} {
+		for _, record := range records[source] {
+			if v, ok := record[field].(float64); ok && v > highest {
+				highest = v
+			}
+		}
+		if highest > 0 {
+			break
+		}
+	}
+	return highest
+}
+
+// isEndpoint reports whether the endpoint is served
+func isEndpoint(endpoint string) bool {
+	for _, e := range Endpoints {
+		if e == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError writes an error response in the format used by the API
+func writeError(w http.ResponseWriter, status int, detail string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"detail": detail})
+}
@@ -0,0 +1,123 @@
+package openf1test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+func TestServerFilters(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		rawQuery string
+		records  int
+	}{
+		{name: "all records", path: "/v1/drivers", records: 6},
+		{name: "equality", path: "/v1/drivers", rawQuery: "session_key=9157", records: 3},
+		{name: "latest session", path: "/v1/drivers", rawQuery: "session_key=latest", records: 3},
+		{name: "latest meeting and session", path: "/v1/laps", rawQuery: "meeting_key=latest&session_key=latest&driver_number=1", records: 6},
+		{name: "literal operators", path: "/v1/laps", rawQuery: "driver_number=1&lap_number>=2&lap_number<5", records: 3},
+		{name: "escaped operators", path: "/v1/laps", rawQuery: "driver_number=1&lap_number%3E%3D=2&lap_number%3C=5", records: 3},
+		{name: "date without a zone", path: "/v1/race_control", rawQuery: "date>=2023-09-17T12:05:00", records: 4},
+		{name: "escaped date in another zone", path: "/v1/race_control", rawQuery: "date%3E=2023-09-17T20%3A05%3A00%2B08%3A00", records: 3},
+		{name: "date range", path: "/v1/race_control", rawQuery: "date>2023-09-17T12:00:00&date<2023-09-17T12:04:00", records: 3},
+		{name: "null value", path: "/v1/laps", rawQuery: "date_start=null", records: 3},
+		{name: "text value", path: "/v1/race_control", rawQuery: "category=SafetyCar", records: 2},
+		{name: "no match", path: "/v1/drivers", rawQuery: "driver_number=99", records: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(s.URL + tt.path + "?" + tt.rawQuery)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			var records []map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != tt.records {
+				t.Errorf("returned %d records, want %d", len(records), tt.records)
+			}
+		})
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		fault  int
+		status int
+	}{
+		{name: "unknown endpoint", path: "/v1/tyres", status: http.StatusNotFound},
+		{name: "missing version", path: "/drivers", status: http.StatusNotFound},
+		{name: "invalid query", path: "/v1/drivers?driver_number=%zz", status: http.StatusBadRequest},
+		{name: "injected fault", path: "/v1/drivers", fault: http.StatusServiceUnavailable, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.fault != 0 {
+				s.FailNext(tt.fault, 1)
+			}
+
+			resp, err := http.Get(s.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestServerClient(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+
+	drivers, err := client.GetDrivers(openf1go.Driver{SessionKey: 9158})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drivers) != 3 {
+		t.Errorf("GetDrivers() returned %d drivers, want 3", len(drivers))
+	}
+
+	// Faults are served in order and then the server recovers
+	s.FailNext(http.StatusTooManyRequests, 1)
+	s.FailNext(http.StatusInternalServerError, 1)
+	if _, err := client.Raw(context.Background(), "drivers", nil); !errors.Is(err, openf1go.ErrRateLimited) {
+		t.Errorf("first request returned %v, want ErrRateLimited", err)
+	}
+	if _, err := client.Raw(context.Background(), "drivers", nil); !errors.Is(err, openf1go.ErrUnexpectedStatus) {
+		t.Errorf("second request returned %v, want ErrUnexpectedStatus", err)
+	}
+	if _, err := client.Raw(context.Background(), "drivers", nil); err != nil {
+		t.Errorf("third request returned %v", err)
+	}
+
+	if got := s.Requests(); got != 4 {
+		t.Errorf("Requests() = %d, want 4", got)
+	}
+}
@@ -41,7 +41,7 @@ func (c *Client) GetPits(pit Pit) (PitResponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err // Return an error if the HTTP request fails
 	}
@@ -65,7 +65,7 @@ func (c *Client) GetAllDriversLatestPits() (PitResponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err // Return an error if the HTTP request fails
 	}
@@ -89,7 +89,7 @@ func (c *Client) GetDriverLatestPits(driver Driver) (PitResponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err // Return an error if the HTTP request fails
 	}
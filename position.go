@@ -39,7 +39,7 @@ func (c *Client) GetPositions(position Position) (PostionsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +63,7 @@ func (c *Client) GetAllDriversLatestPositions() (PostionsResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +87,7 @@ func (c *Client) GetDriversLatestPositions(driver Driver) (PostionsResponse, err
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
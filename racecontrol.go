@@ -45,7 +45,7 @@ func (c *Client) GetRaceControl(raceControl RaceControl) (RaceControlResponse, e
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +69,7 @@ func (c *Client) GetAllDriversLatestRaceControl() (RaceControlResponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +93,7 @@ func (c *Client) GetDriverLatestRaceControl(driver Driver) (RaceControlResponse,
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,278 @@
+package openf1go
+
+// Classifies race control messages into typed events such as penalties, investigations and deleted lap times.
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RaceControlEvent represents a typed event parsed from a race control message.
+// Implementations are LapTimeDeleted, Penalty, UnderInvestigation, NoFurtherAction and BlueFlag.
+type RaceControlEvent interface {
+	Source() RaceControl // The race control message the event was parsed from
+	Drivers() []int      // Driver numbers involved in the event
+}
+
+// eventBase holds the fields shared by all race control events
+type eventBase struct {
+	RaceControl  RaceControl // The race control message the event was parsed from
+	DriverNumber int         // Driver the event applies to
+	LapNumber    int         // Lap on which the event occurred
+	Turn         int         // Turn at which the event occurred, zero when not given
+}
+
+// Source returns the race control message the event was parsed from
+func (e eventBase) Source() RaceControl {
+	return e.RaceControl
+}
+
+// Drivers returns the driver the event applies to
+func (e eventBase) Drivers() []int {
+	if e.DriverNumber == 0 {
+		return nil
+	}
+	return []int{e.DriverNumber}
+}
+
+// LapTimeDeleted represents a lap time deleted by the stewards
+type LapTimeDeleted struct {
+	eventBase
+	Acronym string        // Acronym of the driver as shown in the message
+	LapTime time.Duration // The deleted lap time
+	Reason  string        // Reason for the deletion, e.g. "TRACK LIMITS"
+}
+
+// PenaltyKind identifies the type of penalty
+type PenaltyKind string
+
+const (
+	PenaltyTime         PenaltyKind = "TIME"          // Time penalty added to the race time or served at a stop
+	PenaltyStopGo       PenaltyKind = "STOP/GO"       // Stop and go penalty
+	PenaltyDriveThrough PenaltyKind = "DRIVE THROUGH" // Drive through penalty
+	PenaltyGrid         PenaltyKind = "GRID"          // Grid place penalty for the next race
+	PenaltyOther        PenaltyKind = "OTHER"         // Any other penalty
+)
+
+// Penalty represents a penalty handed to a driver
+type Penalty struct {
+	eventBase
+	Kind    PenaltyKind // Type of penalty
+	Seconds int         // Length of a time or stop/go penalty in seconds
+	Places  int         // Number of grid places for a grid penalty
+	Reason  string      // Reason given for the penalty
+}
+
+// UnderInvestigation represents an incident noted or under investigation by the stewards
+type UnderInvestigation struct {
+	eventBase
+	Involved []int  // All driver numbers involved in the incident
+	Noted    bool   // Indicates if the incident was only noted
+	Reason   string // Description of the incident
+}
+
+// Drivers returns all drivers involved in the incident
+func (u UnderInvestigation) Drivers() []int {
+	return u.Involved
+}
+
+// NoFurtherAction represents the stewards deciding not to act on an incident
+type NoFurtherAction struct {
+	eventBase
+	Involved []int  // All driver numbers involved in the incident
+	Reason   string // Description of the incident
+}
+
+// Drivers returns all drivers involved in the incident
+func (n NoFurtherAction) Drivers() []int {
+	return n.Involved
+}
+
+// BlueFlag represents a blue flag shown to a driver
+type BlueFlag struct {
+	eventBase
+}
+
+var (
+	// Matches "CAR 16 (LEC) TIME 1:29.123 DELETED - TRACK LIMITS AT TURN 4 LAP 12 14:02:33"
+	lapDeletedPattern = regexp.MustCompile(`CAR (\d+) \((\w+)\) (?:LAP )?TIME (\d+:\d+\.\d+) DELETED(?: - (.*?))?(?: AT TURN \d+)?(?: LAP \d+)?(?: \d+:\d+:\d+)?$`)
+	// Matches "5 SECOND TIME PENALTY FOR CAR 44 (HAM) - CAUSING A COLLISION"
+	timePenaltyPattern = regexp.MustCompile(`(\d+) SECOND (TIME|STOP/GO) PENALTY FOR CAR (\d+)(?: \(\w+\))?(?: - (.*))?`)
+	// Matches "DRIVE THROUGH PENALTY FOR CAR 1 (VER) - PIT LANE SPEEDING"
+	driveThroughPattern = regexp.MustCompile(`DRIVE THROUGH PENALTY FOR CAR (\d+)(?: \(\w+\))?(?: - (.*))?`)
+	// Matches "3 PLACE GRID PENALTY FOR CAR 4 (NOR) - IMPEDING"
+	gridPenaltyPattern = regexp.MustCompile(`(\d+) PLACE GRID PENALTY FOR CAR (\d+)(?: \(\w+\))?(?: - (.*))?`)
+	// Matches "PENALTY FOR CAR 11 ..." when no other penalty pattern applies
+	otherPenaltyPattern = regexp.MustCompile(`PENALTY FOR CAR (\d+)(?: \(\w+\))?(?: - (.*))?`)
+	// Matches the cars listed in incident messages, e.g. "CARS 1 (VER) AND 44 (HAM)"
+	carNumbersPattern = regexp.MustCompile(`CARS? ((?:\d+(?: \(\w+\))?(?:,? AND |, )?)+)`)
+	// Matches individual car numbers within a list of cars
+	carNumberPattern = regexp.MustCompile(`(\d+)(?: \(\w+\))?`)
+	// Matches "WAVED BLUE FLAG FOR CAR 2 (SAR) TIMED AT 15:04:05"
+	blueFlagPattern = regexp.MustCompile(`BLUE FLAG FOR CAR (\d+)`)
+	// Matches the turn mentioned in a message, e.g. "TURN 4"
+	turnPattern = regexp.MustCompile(`\bTURN (\d+)`)
+	// Matches the lap mentioned in a message, e.g. "LAP 12"
+	lapPattern = regexp.MustCompile(`\bLAP (\d+)`)
+	// Matches the reason after an incident, e.g. "- CAUSING A COLLISION"
+	incidentReasonPattern = regexp.MustCompile(` - (.*?)(?: - .*)?$`)
+)
+
+// Events classifies every message into a typed event, skipping messages that match no known event
+func (r RaceControlResponse) Events() []RaceControlEvent {
+	events := []RaceControlEvent{}
+
+	for _, rc := range r {
+		if event, ok := rc.Event(); ok {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// Event classifies the message into a typed event.
+// Returns false when the message does not match any known event.
+func (rc RaceControl) Event() (RaceControlEvent, bool) {
+	message := strings.ToUpper(strings.TrimSpace(rc.Message))
+	base := eventBase{RaceControl: rc, DriverNumber: rc.DriverNumber, LapNumber: rc.LapNumber}
+
+	// Prefer the lap and turn named in the message over the lap the message was sent on
+	if m := lapPattern.FindStringSubmatch(message); m != nil {
+		base.LapNumber = atoi(m[1])
+	}
+	if m := turnPattern.FindStringSubmatch(message); m != nil {
+		base.Turn = atoi(m[1])
+	}
+
+	switch {
+	case strings.Contains(message, "DELETED"):
+		m := lapDeletedPattern.FindStringSubmatch(message)
+		if m == nil {
+			return nil, false
+		}
+		event := LapTimeDeleted{eventBase: base, Acronym: m[2], LapTime: parseLapTime(m[3]), Reason: strings.TrimSpace(m[4])}
+		event.DriverNumber = atoi(m[1])
+		return event, true
+
+	case strings.Contains(message, "NO FURTHER ACTION") || strings.Contains(message, "NO FURTHER INVESTIGATION"):
+		event := NoFurtherAction{eventBase: base, Involved: involvedCars(message), Reason: incidentReason(message)}
+		event.DriverNumber = firstOrZero(event.Involved, rc.DriverNumber)
+		return event, true
+
+	case strings.Contains(message, "UNDER INVESTIGATION") || strings.Contains(message, "NOTED"):
+		event := UnderInvestigation{eventBase: base, Involved: involvedCars(message), Noted: !strings.Contains(message, "UNDER INVESTIGATION"), Reason: incidentReason(message)}
+		event.DriverNumber = firstOrZero(event.Involved, rc.DriverNumber)
+		return event, true
+
+	case strings.Contains(message, "PENALTY") && !strings.Contains(message, "SERVED"):
+		return parsePenalty(base, message)
+
+	case strings.Contains(message, "BLUE FLAG") || strings.EqualFold(rc.Flag, "BLUE"):
+		event := BlueFlag{eventBase: base}
+		if m := blueFlagPattern.FindStringSubmatch(message); m != nil {
+			event.DriverNumber = atoi(m[1])
+		}
+		return event, true
+	}
+
+	return nil, false
+}
+
+// parsePenalty parses the penalty messages handed out by the stewards
+func parsePenalty(base eventBase, message string) (RaceControlEvent, bool) {
+	event := Penalty{eventBase: base}
+
+	if m := timePenaltyPattern.FindStringSubmatch(message); m != nil {
+		event.Kind = PenaltyTime
+		if m[2] == "STOP/GO" {
+			event.Kind = PenaltyStopGo
+		}
+		event.Seconds = atoi(m[1])
+		event.DriverNumber = atoi(m[3])
+		event.Reason = strings.TrimSpace(m[4])
+		return event, true
+	}
+
+	if m := driveThroughPattern.FindStringSubmatch(message); m != nil {
+		event.Kind = PenaltyDriveThrough
+		event.DriverNumber = atoi(m[1])
+		event.Reason = strings.TrimSpace(m[2])
+		return event, true
+	}
+
+	if m := gridPenaltyPattern.FindStringSubmatch(message); m != nil {
+		event.Kind = PenaltyGrid
+		event.Places = atoi(m[1])
+		event.DriverNumber = atoi(m[2])
+		event.Reason = strings.TrimSpace(m[3])
+		return event, true
+	}
+
+	if m := otherPenaltyPattern.FindStringSubmatch(message); m != nil {
+		event.Kind = PenaltyOther
+		event.DriverNumber = atoi(m[1])
+		event.Reason = strings.TrimSpace(m[2])
+		return event, true
+	}
+
+	return nil, false
+}
+
+// involvedCars returns the car numbers listed in an incident message
+func involvedCars(message string) []int {
+	m := carNumbersPattern.FindStringSubmatch(message)
+	if m == nil {
+		return nil
+	}
+
+	cars := []int{}
+	for _, car := range carNumberPattern.FindAllStringSubmatch(m[1], -1) {
+		cars = append(cars, atoi(car[1]))
+	}
+
+	return cars
+}
+
+// incidentReason returns the description following the car list of an incident message
+func incidentReason(message string) string {
+	if m := incidentReasonPattern.FindStringSubmatch(message); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// parseLapTime converts a lap time such as "1:29.123" into a duration
+func parseLapTime(s string) time.Duration {
+	minutes, seconds, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0
+	}
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0
+	}
+	sec, err := strconv.ParseFloat(seconds, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(m)*time.Minute + time.Duration(sec*float64(time.Second))
+}
+
+// atoi converts a string to an int, returning zero for empty or invalid strings
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// firstOrZero returns the first number of a list or the fallback when the list is empty
+func firstOrZero(numbers []int, fallback int) int {
+	if len(numbers) > 0 {
+		return numbers[0]
+	}
+	return fallback
+}
@@ -0,0 +1,135 @@
+package openf1go
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRaceControlEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		rc      RaceControl
+		want    RaceControlEvent
+		matches bool
+	}{
+		{
+			name: "lap time deleted",
+			rc:   RaceControl{LapNumber: 13, Message: "CAR 16 (LEC) TIME 1:29.123 DELETED - TRACK LIMITS AT TURN 4 LAP 12 14:02:33"},
+			want: LapTimeDeleted{
+				eventBase: eventBase{DriverNumber: 16, LapNumber: 12, Turn: 4},
+				Acronym:   "LEC", LapTime: time.Minute + 29123*time.Millisecond, Reason: "TRACK LIMITS",
+			},
+			matches: true,
+		},
+		{
+			name:    "time penalty",
+			rc:      RaceControl{LapNumber: 20, Message: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 44 (HAM) - CAUSING A COLLISION"},
+			want:    Penalty{eventBase: eventBase{DriverNumber: 44, LapNumber: 20}, Kind: PenaltyTime, Seconds: 5, Reason: "CAUSING A COLLISION"},
+			matches: true,
+		},
+		{
+			name:    "stop and go penalty",
+			rc:      RaceControl{Message: "FIA STEWARDS: 10 SECOND STOP/GO PENALTY FOR CAR 20 (MAG) - UNSAFE RELEASE"},
+			want:    Penalty{eventBase: eventBase{DriverNumber: 20}, Kind: PenaltyStopGo, Seconds: 10, Reason: "UNSAFE RELEASE"},
+			matches: true,
+		},
+		{
+			name:    "drive through penalty",
+			rc:      RaceControl{Message: "FIA STEWARDS: DRIVE THROUGH PENALTY FOR CAR 1 (VER) - PIT LANE SPEEDING"},
+			want:    Penalty{eventBase: eventBase{DriverNumber: 1}, Kind: PenaltyDriveThrough, Reason: "PIT LANE SPEEDING"},
+			matches: true,
+		},
+		{
+			name:    "grid penalty",
+			rc:      RaceControl{Message: "FIA STEWARDS: 3 PLACE GRID PENALTY FOR CAR 4 (NOR) - IMPEDING"},
+			want:    Penalty{eventBase: eventBase{DriverNumber: 4}, Kind: PenaltyGrid, Places: 3, Reason: "IMPEDING"},
+			matches: true,
+		},
+		{
+			name:    "other penalty",
+			rc:      RaceControl{Message: "FIA STEWARDS: REPRIMAND AND PENALTY FOR CAR 11 (PER) - DRIVING STANDARDS"},
+			want:    Penalty{eventBase: eventBase{DriverNumber: 11}, Kind: PenaltyOther, Reason: "DRIVING STANDARDS"},
+			matches: true,
+		},
+		{
+			name: "under investigation",
+			rc:   RaceControl{LapNumber: 2, Message: "FIA STEWARDS: LAP 1 TURN 1 INCIDENT INVOLVING CARS 1 (VER) AND 44 (HAM) UNDER INVESTIGATION - CAUSING A COLLISION"},
+			want: UnderInvestigation{
+				eventBase: eventBase{DriverNumber: 1, LapNumber: 1, Turn: 1},
+				Involved:  []int{1, 44}, Reason: "CAUSING A COLLISION",
+			},
+			matches: true,
+		},
+		{
+			name: "noted",
+			rc:   RaceControl{DriverNumber: 16, Message: "FIA STEWARDS: TURN 4 INCIDENT INVOLVING CAR 16 (LEC) NOTED - LEAVING THE TRACK"},
+			want: UnderInvestigation{
+				eventBase: eventBase{DriverNumber: 16, Turn: 4},
+				Involved:  []int{16}, Noted: true, Reason: "LEAVING THE TRACK",
+			},
+			matches: true,
+		},
+		{
+			name: "no further action",
+			rc:   RaceControl{Message: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 1 (VER), 16 (LEC) AND 44 (HAM) REVIEWED NO FURTHER INVESTIGATION"},
+			want: NoFurtherAction{
+				eventBase: eventBase{DriverNumber: 1, Turn: 1},
+				Involved:  []int{1, 16, 44},
+			},
+			matches: true,
+		},
+		{
+			name:    "blue flag",
+			rc:      RaceControl{Flag: "BLUE", Message: "WAVED BLUE FLAG FOR CAR 2 (SAR) TIMED AT 15:04:05"},
+			want:    BlueFlag{eventBase: eventBase{DriverNumber: 2}},
+			matches: true,
+		},
+		{
+			name: "penalty served",
+			rc:   RaceControl{Message: "FIA STEWARDS: 5 SECOND TIME PENALTY FOR CAR 44 (HAM) SERVED"},
+		},
+		{
+			name: "flag",
+			rc:   RaceControl{Flag: "GREEN", Message: "GREEN LIGHT - PIT EXIT OPEN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.rc.Event()
+			if ok != tt.matches {
+				t.Fatalf("Event() matched = %v, want %v: %+v", ok, tt.matches, got)
+			}
+			if !ok {
+				return
+			}
+
+			// The expected events leave the source message out
+			want := reflect.ValueOf(&tt.want).Elem()
+			event := reflect.New(want.Elem().Type()).Elem()
+			event.Set(want.Elem())
+			event.FieldByName("RaceControl").Set(reflect.ValueOf(tt.rc))
+
+			if !reflect.DeepEqual(got, event.Interface()) {
+				t.Errorf("Event() = %+v, want %+v", got, event.Interface())
+			}
+		})
+	}
+}
+
+func TestRaceControlResponseEvents(t *testing.T) {
+	messages := RaceControlResponse{
+		{Message: "GREEN LIGHT - PIT EXIT OPEN"},
+		{Message: "CAR 16 (LEC) TIME 1:29.123 DELETED - TRACK LIMITS AT TURN 4 LAP 12 14:02:33"},
+		{Message: "FIA STEWARDS: TURN 1 INCIDENT INVOLVING CARS 1 (VER) AND 44 (HAM) NOTED - CAUSING A COLLISION"},
+	}
+
+	events := messages.Events()
+	if len(events) != 2 {
+		t.Fatalf("Events() returned %d events, want 2", len(events))
+	}
+	if drivers := events[1].Drivers(); !reflect.DeepEqual(drivers, []int{1, 44}) {
+		t.Errorf("Drivers() = %v, want [1 44]", drivers)
+	}
+}
@@ -0,0 +1,370 @@
+package replay
+
+// Replays a historical session as a time ordered stream of events on a controllable virtual clock.
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// LoadOptions configures which data is loaded for a replay
+type LoadOptions struct {
+	SkipTelemetry bool // Skip car data and location, which make up most of the volume of a session
+}
+
+// Replayer emits the events of a session on a virtual clock that can be paused, sought and sped up.
+// Controls are safe for concurrent use while Run is emitting events.
+type Replayer struct {
+	events []openf1go.Event // Events ordered by date
+
+	mu      sync.Mutex
+	next    int           // Index of the next event to emit
+	virtual time.Time     // Virtual time at the moment the clock was last anchored
+	real    time.Time     // Real time at the moment the clock was last anchored
+	speed   float64       // Speed multiplier of the virtual clock
+	playing bool          // Indicates if the virtual clock is running
+	wake    chan struct{} // Signals Run that the clock was changed
+	now     func() time.Time
+}
+
+// New creates a paused Replayer for the given events positioned at the first event
+func New(events []openf1go.Event) *Replayer {
+	sorted := append([]openf1go.Event{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	r := &Replayer{
+		events: sorted,
+		speed:  1,
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+	if len(sorted) > 0 {
+		r.virtual = sorted[0].Date
+	}
+
+	return r
+}
+
+// Load fetches every endpoint of a session and creates a paused Replayer for it.
+// Drivers are emitted at the start of the session and stints at the start of their first lap.
+func Load(c *openf1go.Client, session openf1go.Session, opts LoadOptions) (*Replayer, error) {
+	// Validate that the session has a valid session key
+	if session.SessionKey == 0 {
+		return nil, openf1go.ErrSessionKeyMissing
+	}
+	key := session.SessionKey
+	events := []openf1go.Event{}
+
+	drivers, err := c.GetDrivers(openf1go.Driver{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drivers {
+		event := openf1go.NewEvent(d)
+		event.Date = session.DateStart
+		events = append(events, event)
+	}
+
+	laps, err := c.GetLaps(openf1go.Lap{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	lapStarts := map[[2]int]time.Time{}
+	for _, l := range laps {
+		lapStarts[[2]int{l.DriverNumber, l.LapNumber}] = l.DateStart
+		events = append(events, lapEvent(l, session))
+	}
+
+	stints, err := c.GetStints(openf1go.Stint{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stints {
+		event := openf1go.NewEvent(s)
+		event.Date = lapStarts[[2]int{s.DriverNumber, s.LapStart}]
+		if event.Date.IsZero() {
+			event.Date = session.DateStart
+		}
+		events = append(events, event)
+	}
+
+	positions, err := c.GetPositions(openf1go.Position{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		events = append(events, openf1go.NewEvent(p))
+	}
+
+	intervals, err := c.GetIntervals(openf1go.Interval{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range intervals {
+		events = append(events, openf1go.NewEvent(i))
+	}
+
+	pits, err := c.GetPits(openf1go.Pit{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pits {
+		events = append(events, openf1go.NewEvent(p))
+	}
+
+	raceControl, err := c.GetRaceControl(openf1go.RaceControl{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, rc := range raceControl {
+		events = append(events, openf1go.NewEvent(rc))
+	}
+
+	teamRadio, err := c.GetTeamRadio(openf1go.TeamRadio{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range teamRadio {
+		events = append(events, openf1go.NewEvent(t))
+	}
+
+	weather, err := c.GetWeather(openf1go.Weather{SessionKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range weather {
+		events = append(events, openf1go.NewEvent(w))
+	}
+
+	// Telemetry is fetched per driver to keep responses to a reasonable size
+	if !opts.SkipTelemetry {
+		for _, d := range drivers {
+			carData, err := c.GetCarData(openf1go.CarData{SessionKey: key, DriverNumber: d.DriverNumber})
+			if err != nil {
+				return nil, err
+			}
+			for _, cd := range carData {
+				events = append(events, openf1go.NewEvent(cd))
+			}
+
+			locations, err := c.GetLocations(openf1go.Location{SessionKey: key, DriverNumber: d.DriverNumber})
+			if err != nil {
+				return nil, err
+			}
+			for _, l := range locations {
+				events = append(events, openf1go.NewEvent(l))
+			}
+		}
+	}
+
+	return New(events), nil
+}
+
+// lapEvent returns the event of a lap, emitted once the lap is complete as it would be in a live session.
+// Laps without a start or a duration are emitted at the start of the session.
+func lapEvent(l openf1go.Lap, session openf1go.Session) openf1go.Event {
+	event := openf1go.NewEvent(l)
+	switch {
+	case !l.DateStart.IsZero() && l.LapDuration > 0:
+		event.Date = l.DateStart.Add(time.Duration(l.LapDuration * float64(time.Second)))
+	case l.DateStart.IsZero():
+		event.Date = session.DateStart
+	}
+	return event
+}
+
+// Len returns the number of events in the replay
+func (r *Replayer) Len() int {
+	return len(r.events)
+}
+
+// Start returns the date of the first event
+func (r *Replayer) Start() time.Time {
+	if len(r.events) == 0 {
+		return time.Time{}
+	}
+	return r.events[0].Date
+}
+
+// End returns the date of the last event
+func (r *Replayer) End() time.Time {
+	if len(r.events) == 0 {
+		return time.Time{}
+	}
+	return r.events[len(r.events)-1].Date
+}
+
+// Now returns the current virtual time
+func (r *Replayer) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.clock()
+}
+
+// clock returns the current virtual time. The caller must hold the lock.
+func (r *Replayer) clock() time.Time {
+	if !r.playing {
+		return r.virtual
+	}
+	elapsed := r.now().Sub(r.real)
+	return r.virtual.Add(time.Duration(float64(elapsed) * r.speed))
+}
+
+// anchor fixes the virtual time at the current moment so the clock can be changed. The caller must hold the lock.
+func (r *Replayer) anchor() {
+	r.virtual = r.clock()
+	r.real = r.now()
+}
+
+// signal wakes up Run so it picks up a change of the clock
+func (r *Replayer) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Play starts or resumes the virtual clock
+func (r *Replayer) Play() {
+	r.mu.Lock()
+	r.anchor()
+	r.playing = true
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Pause stops the virtual clock
+func (r *Replayer) Pause() {
+	r.mu.Lock()
+	r.anchor()
+	r.playing = false
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Playing reports whether the virtual clock is running
+func (r *Replayer) Playing() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.playing
+}
+
+// SetSpeed changes the speed multiplier of the virtual clock, e.g. 10 replays ten times faster than real time.
+// Values of zero or below are ignored.
+func (r *Replayer) SetSpeed(speed float64) {
+	if speed <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.anchor()
+	r.speed = speed
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Seek moves the virtual clock to the given time. The next event emitted is the first one at or after it.
+// Consumers keeping state should reset it when seeking backwards.
+func (r *Replayer) Seek(t time.Time) {
+	r.mu.Lock()
+	r.anchor()
+	r.virtual = t
+	r.next = sort.Search(len(r.events), func(i int) bool { return !r.events[i].Date.Before(t) })
+	r.mu.Unlock()
+	r.signal()
+}
+
+// FastForward moves the virtual clock forward to the given time and returns the events it passed,
+// so consumers keeping state can apply them at once instead of waiting for them.
+func (r *Replayer) FastForward(t time.Time) []openf1go.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skipped := []openf1go.Event{}
+	for r.next < len(r.events) && r.events[r.next].Date.Before(t) {
+		skipped = append(skipped, r.events[r.next])
+		r.next++
+	}
+
+	r.anchor()
+	if t.After(r.virtual) {
+		r.virtual = t
+	}
+	r.signal()
+
+	return skipped
+}
+
+// Run emits events as the virtual clock passes their date until every event is emitted or the context is done.
+// The returned channel is closed when Run stops.
+func (r *Replayer) Run(ctx context.Context) <-chan openf1go.Event {
+	out := make(chan openf1go.Event, 256)
+
+	go func() {
+		defer close(out)
+
+		timer := time.NewTimer(time.Hour)
+		defer timer.Stop()
+
+		for {
+			event, wait, done := r.due()
+			if done {
+				return
+			}
+
+			// Emit a due event, otherwise sleep until the next one or until the clock changes
+			if wait <= 0 {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-r.wake:
+				if !timer.Stop() {
+					<-timer.C
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// due returns the next event if it is due, otherwise how long to wait in real time before checking again.
+// done is true when every event has been emitted.
+func (r *Replayer) due() (event openf1go.Event, wait time.Duration, done bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next >= len(r.events) {
+		return openf1go.Event{}, 0, true
+	}
+
+	// Paused clocks wait until they are resumed
+	if !r.playing {
+		return openf1go.Event{}, time.Hour, false
+	}
+
+	next := r.events[r.next]
+	ahead := next.Date.Sub(r.clock())
+	if ahead > 0 {
+		return openf1go.Event{}, time.Duration(float64(ahead) / r.speed), false
+	}
+
+	r.next++
+	return next, 0, false
+}
@@ -0,0 +1,275 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+var sessionStart = time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// fakeClock is a real time clock moved forward by hand
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// testEvents returns a position event every ten seconds of the session, given out of order
+func testEvents() []openf1go.Event {
+	events := []openf1go.Event{}
+	for _, seconds := range []int{20, 0, 10, 30} {
+		date := sessionStart.Add(time.Duration(seconds) * time.Second)
+		events = append(events, openf1go.NewEvent(openf1go.Position{DriverNumber: 1, Position: seconds/10 + 1, Date: date}))
+	}
+	return events
+}
+
+// newTestReplayer creates a Replayer of testEvents on a fake clock
+func newTestReplayer() (*Replayer, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := New(testEvents())
+	r.now = clock.Now
+	return r, clock
+}
+
+// at returns the time the given number of seconds into the session
+func at(seconds int) time.Time {
+	return sessionStart.Add(time.Duration(seconds) * time.Second)
+}
+
+func TestReplayerClock(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(r *Replayer, clock *fakeClock)
+		want  time.Time // Virtual time after the steps
+	}{
+		{name: "paused at the first event", steps: func(r *Replayer, clock *fakeClock) { clock.Advance(time.Minute) }, want: at(0)},
+		{
+			name: "playing",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.Play()
+				clock.Advance(10 * time.Second)
+			},
+			want: at(10),
+		},
+		{
+			name: "paused after playing",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.Play()
+				clock.Advance(10 * time.Second)
+				r.Pause()
+				clock.Advance(time.Minute)
+			},
+			want: at(10),
+		},
+		{
+			name: "speed change keeps the time already played",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.Play()
+				clock.Advance(10 * time.Second)
+				r.SetSpeed(4)
+				clock.Advance(5 * time.Second)
+			},
+			want: at(30),
+		},
+		{
+			name: "invalid speed ignored",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.SetSpeed(0)
+				r.SetSpeed(-2)
+				r.Play()
+				clock.Advance(10 * time.Second)
+			},
+			want: at(10),
+		},
+		{
+			name: "seek while playing",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.Play()
+				clock.Advance(10 * time.Second)
+				r.Seek(at(25))
+				clock.Advance(2 * time.Second)
+			},
+			want: at(27),
+		},
+		{
+			name: "seek while paused",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.Seek(at(25))
+				clock.Advance(time.Minute)
+			},
+			want: at(25),
+		},
+		{
+			name: "fast forward",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.FastForward(at(15))
+			},
+			want: at(15),
+		},
+		{
+			name: "fast forward does not go back",
+			steps: func(r *Replayer, clock *fakeClock) {
+				r.Seek(at(25))
+				r.FastForward(at(15))
+			},
+			want: at(25),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, clock := newTestReplayer()
+			tt.steps(r, clock)
+			if got := r.Now(); !got.Equal(tt.want) {
+				t.Errorf("Now() = %v into the session, want %v", got.Sub(sessionStart), tt.want.Sub(sessionStart))
+			}
+		})
+	}
+}
+
+func TestReplayerFastForward(t *testing.T) {
+	tests := []struct {
+		name    string
+		seek    time.Time // Time sought before fast forwarding, zero to start from the first event
+		to      time.Time
+		skipped []int // Positions of the events passed
+	}{
+		{name: "past two events", to: at(15), skipped: []int{1, 2}},
+		{name: "up to an event", to: at(10), skipped: []int{1}},
+		{name: "before the first event", to: at(-10), skipped: []int{}},
+		{name: "past the end", to: at(60), skipped: []int{1, 2, 3, 4}},
+		{name: "after seeking", seek: at(10), to: at(25), skipped: []int{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestReplayer()
+			if !tt.seek.IsZero() {
+				r.Seek(tt.seek)
+			}
+
+			skipped := []int{}
+			for _, event := range r.FastForward(tt.to) {
+				skipped = append(skipped, event.Data.(openf1go.Position).Position)
+			}
+			if len(skipped) != len(tt.skipped) {
+				t.Fatalf("FastForward() passed %v, want %v", skipped, tt.skipped)
+			}
+			for i := range skipped {
+				if skipped[i] != tt.skipped[i] {
+					t.Fatalf("FastForward() passed %v, want %v", skipped, tt.skipped)
+				}
+			}
+		})
+	}
+}
+
+// receive returns the positions of the events received until none arrive for a while
+func receive(events <-chan openf1go.Event) []int {
+	positions := []int{}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return positions
+			}
+			positions = append(positions, event.Data.(openf1go.Position).Position)
+		case <-time.After(100 * time.Millisecond):
+			return positions
+		}
+	}
+}
+
+func TestReplayerRun(t *testing.T) {
+	r, clock := newTestReplayer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := r.Run(ctx)
+
+	// Nothing is emitted while paused
+	if got := receive(events); len(got) != 0 {
+		t.Fatalf("paused Run() emitted %v", got)
+	}
+
+	// The fake clock does not wake Run, so each step ends with a control that does
+	steps := []struct {
+		name    string
+		step    func()
+		emitted []int
+	}{
+		{name: "play emits the event at the current time", step: r.Play, emitted: []int{1}},
+		{name: "events as the clock passes them", step: func() { clock.Advance(10 * time.Second); r.SetSpeed(2) }, emitted: []int{2}},
+		{name: "faster clock", step: func() { clock.Advance(5 * time.Second); r.Play() }, emitted: []int{3}},
+		{name: "pause", step: func() { r.Pause(); clock.Advance(time.Hour); r.Pause() }, emitted: []int{}},
+		{name: "seek back while paused", step: func() { r.Seek(at(5)) }, emitted: []int{}},
+		{name: "resume after seeking back", step: func() { r.Play(); clock.Advance(30 * time.Second); r.Play() }, emitted: []int{2, 3, 4}},
+	}
+
+	for _, tt := range steps {
+		tt.step()
+		got := receive(events)
+		if len(got) != len(tt.emitted) {
+			t.Fatalf("%s: Run() emitted %v, want %v", tt.name, got, tt.emitted)
+		}
+		for i := range got {
+			if got[i] != tt.emitted[i] {
+				t.Fatalf("%s: Run() emitted %v, want %v", tt.name, got, tt.emitted)
+			}
+		}
+	}
+
+	// Run stops once every event is emitted
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Run() emitted an event after the last one")
+		}
+	case <-time.After(time.Second):
+		t.Error("Run() did not stop after the last event")
+	}
+}
+
+func TestReplayerRunCancel(t *testing.T) {
+	r, _ := newTestReplayer()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := r.Run(ctx)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Run() emitted an event after the context was cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Error("Run() did not stop when the context was cancelled")
+	}
+}
+
+func TestNewEmpty(t *testing.T) {
+	r := New(nil)
+	if r.Len() != 0 || !r.Start().IsZero() || !r.End().IsZero() {
+		t.Errorf("New(nil) has %d events from %v to %v", r.Len(), r.Start(), r.End())
+	}
+
+	events := r.Run(context.Background())
+	if _, ok := <-events; ok {
+		t.Error("Run() of an empty replay emitted an event")
+	}
+}
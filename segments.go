@@ -0,0 +1,135 @@
+package openf1go
+
+// Decodes the mini-sector segment codes reported in Lap.SegmentsSector1, Lap.SegmentsSector2 and Lap.SegmentsSector3.
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Segment represents the status code of a single mini-sector
+type Segment int
+
+const (
+	SegmentNotAvailable Segment = 0    // No data for the mini-sector
+	SegmentYellow       Segment = 2048 // Mini-sector completed without improvement
+	SegmentGreen        Segment = 2049 // Personal best mini-sector
+	SegmentUnknown2050  Segment = 2050 // Undocumented code
+	SegmentPurple       Segment = 2051 // Overall best mini-sector
+	SegmentUnknown2052  Segment = 2052 // Undocumented code
+	SegmentPitLane      Segment = 2064 // Car was in the pit lane
+	SegmentUnknown2068  Segment = 2068 // Undocumented code
+)
+
+// SegmentColor represents the color a mini-sector is shown with on a timing screen
+type SegmentColor string
+
+const (
+	SegmentColorNone    SegmentColor = "none"    // No data or an undocumented code
+	SegmentColorYellow  SegmentColor = "yellow"  // Completed without improvement
+	SegmentColorGreen   SegmentColor = "green"   // Personal best
+	SegmentColorPurple  SegmentColor = "purple"  // Overall best
+	SegmentColorPitLane SegmentColor = "pitlane" // In the pit lane
+)
+
+// Color returns the display color of the segment
+func (s Segment) Color() SegmentColor {
+	switch s {
+	case SegmentYellow:
+		return SegmentColorYellow
+	case SegmentGreen:
+		return SegmentColorGreen
+	case SegmentPurple:
+		return SegmentColorPurple
+	case SegmentPitLane:
+		return SegmentColorPitLane
+	default:
+		return SegmentColorNone
+	}
+}
+
+// IsPersonalBest reports whether the segment is a personal best, which an overall best also is
+func (s Segment) IsPersonalBest() bool {
+	return s == SegmentGreen || s == SegmentPurple
+}
+
+// IsOverallBest reports whether the segment is the overall best
+func (s Segment) IsOverallBest() bool {
+	return s == SegmentPurple
+}
+
+// IsPitLane reports whether the car was in the pit lane for the segment
+func (s Segment) IsPitLane() bool {
+	return s == SegmentPitLane
+}
+
+// String returns the color of the segment, or its code when the code is undocumented
+func (s Segment) String() string {
+	if c := s.Color(); c != SegmentColorNone || s == SegmentNotAvailable {
+		return string(c)
+	}
+	return "segment(" + strconv.Itoa(int(s)) + ")"
+}
+
+// toSegments converts raw segment codes into Segments
+func toSegments(codes []int) []Segment {
+	segments := make([]Segment, len(codes))
+	for i, code := range codes {
+		segments[i] = Segment(code)
+	}
+	return segments
+}
+
+// MiniSectors returns the decoded mini-sector map of the lap
+func (l Lap) MiniSectors() MiniSectorMap {
+	return MiniSectorMap{
+		toSegments(l.SegmentsSector1),
+		toSegments(l.SegmentsSector2),
+		toSegments(l.SegmentsSector3),
+	}
+}
+
+// MiniSectorMap represents the mini-sectors of a lap grouped by sector
+type MiniSectorMap [3][]Segment
+
+// segmentSymbols maps segment colors to the characters used by String
+var segmentSymbols = map[SegmentColor]string{
+	SegmentColorNone:    ".",
+	SegmentColorYellow:  "Y",
+	SegmentColorGreen:   "G",
+	SegmentColorPurple:  "P",
+	SegmentColorPitLane: "I",
+}
+
+// segmentANSI maps segment colors to ANSI escape sequences used by ANSI
+var segmentANSI = map[SegmentColor]string{
+	SegmentColorNone:    "\x1b[90m",
+	SegmentColorYellow:  "\x1b[33m",
+	SegmentColorGreen:   "\x1b[32m",
+	SegmentColorPurple:  "\x1b[35m",
+	SegmentColorPitLane: "\x1b[37m",
+}
+
+// String renders the map as one character per mini-sector with sectors separated by "|".
+// Purple is "P", green "G", yellow "Y", pit lane "I" and missing data ".".
+func (m MiniSectorMap) String() string {
+	return m.render(func(c SegmentColor) string { return segmentSymbols[c] })
+}
+
+// ANSI renders the map as colored blocks for terminals supporting ANSI escape sequences
+func (m MiniSectorMap) ANSI() string {
+	return m.render(func(c SegmentColor) string { return segmentANSI[c] + "■\x1b[0m" })
+}
+
+// render joins the rendered mini-sectors of each sector with "|"
+func (m MiniSectorMap) render(symbol func(SegmentColor) string) string {
+	sectors := make([]string, len(m))
+	for i, segments := range m {
+		var b strings.Builder
+		for _, s := range segments {
+			b.WriteString(symbol(s.Color()))
+		}
+		sectors[i] = b.String()
+	}
+	return strings.Join(sectors, "|")
+}
@@ -0,0 +1,83 @@
+package openf1go
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		segment      Segment
+		color        SegmentColor
+		personalBest bool
+		overallBest  bool
+		pitLane      bool
+		text         string
+	}{
+		{segment: SegmentNotAvailable, color: SegmentColorNone, text: "none"},
+		{segment: SegmentYellow, color: SegmentColorYellow, text: "yellow"},
+		{segment: SegmentGreen, color: SegmentColorGreen, personalBest: true, text: "green"},
+		{segment: SegmentPurple, color: SegmentColorPurple, personalBest: true, overallBest: true, text: "purple"},
+		{segment: SegmentPitLane, color: SegmentColorPitLane, pitLane: true, text: "pitlane"},
+		{segment: SegmentUnknown2050, color: SegmentColorNone, text: "segment(2050)"},
+		{segment: SegmentUnknown2052, color: SegmentColorNone, text: "segment(2052)"},
+		{segment: SegmentUnknown2068, color: SegmentColorNone, text: "segment(2068)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := tt.segment.Color(); got != tt.color {
+				t.Errorf("Color() = %v, want %v", got, tt.color)
+			}
+			if got := tt.segment.IsPersonalBest(); got != tt.personalBest {
+				t.Errorf("IsPersonalBest() = %v, want %v", got, tt.personalBest)
+			}
+			if got := tt.segment.IsOverallBest(); got != tt.overallBest {
+				t.Errorf("IsOverallBest() = %v, want %v", got, tt.overallBest)
+			}
+			if got := tt.segment.IsPitLane(); got != tt.pitLane {
+				t.Errorf("IsPitLane() = %v, want %v", got, tt.pitLane)
+			}
+			if got := tt.segment.String(); got != tt.text {
+				t.Errorf("String() = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestLapMiniSectors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "all colors",
+			data: `{"segments_sector_1":[2049,2051,2048],"segments_sector_2":[2064,0,2050],"segments_sector_3":[2048,2048]}`,
+			want: "GPY|I..|YY",
+		},
+		{
+			name: "missing sectors",
+			data: `{"segments_sector_1":[2048],"segments_sector_2":null}`,
+			want: "Y||",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lap Lap
+			if err := json.Unmarshal([]byte(tt.data), &lap); err != nil {
+				t.Fatal(err)
+			}
+			m := lap.MiniSectors()
+			if got := m.String(); got != tt.want {
+				t.Errorf("MiniSectors().String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	ansi := MiniSectorMap{{SegmentPurple}, {}, {SegmentNotAvailable}}.ANSI()
+	if want := "\x1b[35m■\x1b[0m||\x1b[90m■\x1b[0m"; ansi != want {
+		t.Errorf("ANSI() = %q, want %q", ansi, want)
+	}
+}
@@ -48,7 +48,7 @@ func (c *Client) GetSessions(session Session) (SessionResponse, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err // Return error if the request fails
 	}
@@ -72,7 +72,7 @@ func (c *Client) GetLatestSessions() (Session, error) {
 	}
 
 	// Make the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return Session{}, err // Return error if the request fails
 	}
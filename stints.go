@@ -42,7 +42,7 @@ func (c *Client) GetStints(stint Stint) (StintsReponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func (c *Client) GetAllDriversLatestStints() (StintsReponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +90,7 @@ func (c *Client) GetDriverLatestStints(driver Driver) (StintsReponse, error) {
 	}
 
 	// Perform the HTTP GET request
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,8 @@
+package storage
+
+import "errors"
+
+var (
+	ErrUnsupportedType = errors.New("unsupported field type")
+	ErrNotFound        = errors.New("record not found")
+)
@@ -0,0 +1,172 @@
+package storage
+
+// Typed queries returning the stored records as the models returned by the client.
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// Query selects stored records of a session. Zero fields do not filter.
+type Query struct {
+	SessionKey   int       // Session the records belong to
+	DriverNumber int       // Driver the records belong to
+	From         time.Time // Earliest date of the records, inclusive
+	To           time.Time // Latest date of the records, exclusive
+}
+
+// where returns the conditions and arguments selecting the records of a table
+func (q Query) where(t table) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	if q.SessionKey != 0 {
+		conditions = append(conditions, "session_key = ?")
+		args = append(args, q.SessionKey)
+	}
+	if q.DriverNumber != 0 && t.hasColumn("driver_number") {
+		conditions = append(conditions, "driver_number = ?")
+		args = append(args, q.DriverNumber)
+	}
+	if t.dated && !q.From.IsZero() {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, q.From.UTC().Format(timeLayout))
+	}
+	if t.dated && !q.To.IsZero() {
+		conditions = append(conditions, "date < ?")
+		args = append(args, q.To.UTC().Format(timeLayout))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Meetings returns the stored meetings of a year ordered by start date
+func (s *Store) Meetings(ctx context.Context, year int) (openf1go.MeetingResponse, error) {
+	return selectRows[openf1go.Meeting](ctx, s, "meetings", " WHERE year = ?", []any{year}, "date_start")
+}
+
+// Sessions returns the stored sessions of a year ordered by start date
+func (s *Store) Sessions(ctx context.Context, year int) (openf1go.SessionResponse, error) {
+	return selectRows[openf1go.Session](ctx, s, "sessions", " WHERE year = ?", []any{year}, "date_start")
+}
+
+// Session returns a stored session, ErrNotFound when it is not stored
+func (s *Store) Session(ctx context.Context, sessionKey int) (openf1go.Session, error) {
+	sessions, err := selectRows[openf1go.Session](ctx, s, "sessions", " WHERE session_key = ?", []any{sessionKey}, "session_key")
+	if err != nil {
+		return openf1go.Session{}, err
+	}
+	if len(sessions) == 0 {
+		return openf1go.Session{}, ErrNotFound
+	}
+	return sessions[0], nil
+}
+
+// Drivers returns the stored drivers matching the query
+func (s *Store) Drivers(ctx context.Context, q Query) (openf1go.DriversResponse, error) {
+	return query[openf1go.Driver](ctx, s, "drivers", q, "driver_number")
+}
+
+// Laps returns the stored laps matching the query ordered by driver and lap
+func (s *Store) Laps(ctx context.Context, q Query) (openf1go.LapsResponse, error) {
+	return query[openf1go.Lap](ctx, s, "laps", q, "driver_number, lap_number")
+}
+
+// Stints returns the stored stints matching the query ordered by driver and stint
+func (s *Store) Stints(ctx context.Context, q Query) (openf1go.StintsReponse, error) {
+	return query[openf1go.Stint](ctx, s, "stints", q, "driver_number, stint_number")
+}
+
+// Pits returns the stored pit stops matching the query ordered by date
+func (s *Store) Pits(ctx context.Context, q Query) (openf1go.PitResponse, error) {
+	return query[openf1go.Pit](ctx, s, "pit", q, "date")
+}
+
+// Positions returns the stored positions matching the query ordered by date
+func (s *Store) Positions(ctx context.Context, q Query) (openf1go.PostionsResponse, error) {
+	return query[openf1go.Position](ctx, s, "position", q, "date")
+}
+
+// Intervals returns the stored intervals matching the query ordered by date
+func (s *Store) Intervals(ctx context.Context, q Query) (openf1go.IntervalsResponse, error) {
+	return query[openf1go.Interval](ctx, s, "intervals", q, "date")
+}
+
+// RaceControl returns the stored race control messages matching the query ordered by date
+func (s *Store) RaceControl(ctx context.Context, q Query) (openf1go.RaceControlResponse, error) {
+	return query[openf1go.RaceControl](ctx, s, "race_control", q, "date")
+}
+
+// TeamRadio returns the stored team radio messages matching the query ordered by date
+func (s *Store) TeamRadio(ctx context.Context, q Query) (openf1go.TeamRadioResponse, error) {
+	return query[openf1go.TeamRadio](ctx, s, "team_radio", q, "date")
+}
+
+// Weather returns the stored weather matching the query ordered by date
+func (s *Store) Weather(ctx context.Context, q Query) (openf1go.WeatherResponse, error) {
+	return query[openf1go.Weather](ctx, s, "weather", q, "date")
+}
+
+// CarData returns the stored car data matching the query ordered by date
+func (s *Store) CarData(ctx context.Context, q Query) (openf1go.CarDataResponse, error) {
+	return query[openf1go.CarData](ctx, s, "car_data", q, "date")
+}
+
+// Locations returns the stored locations matching the query ordered by date
+func (s *Store) Locations(ctx context.Context, q Query) (openf1go.LocationResponse, error) {
+	return query[openf1go.Location](ctx, s, "location", q, "date")
+}
+
+// query returns the records of a table matching the query
+func query[T any](ctx context.Context, s *Store, name string, q Query, orderBy string) ([]T, error) {
+	where, args := q.where(tableOf(name))
+	return selectRows[T](ctx, s, name, where, args, orderBy)
+}
+
+// selectRows returns the records of a table matching the where clause, decoded into the model T
+func selectRows[T any](ctx context.Context, s *Store, name, where string, args []any, orderBy string) ([]T, error) {
+	t := tableOf(name)
+	columns := columnsOf(t.model)
+
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+strings.Join(names, ", ")+" FROM "+t.name+where+" ORDER BY "+orderBy, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []T{}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		var record T
+		v := reflect.ValueOf(&record).Elem()
+		for i, c := range columns {
+			if err := c.fromSQL(v.Field(c.index), values[i]); err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,320 @@
+package storage
+
+// Stores API data in an embedded SQLite database, one table per endpoint with columns named after the API fields.
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, no cgo required
+)
+
+// timeLayout is the layout dates are stored with: UTC with fixed precision so dates sort as text
+const timeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// table describes the table of an endpoint
+type table struct {
+	name      string       // Name of the endpoint and the table
+	model     reflect.Type // Model stored in the table
+	key       []string     // Columns identifying a record, later records replace earlier ones
+	dated     bool         // Records carry a date so only newer records need fetching
+	perDriver bool         // Fetched one driver at a time as the endpoint holds several samples per second
+}
+
+// tables lists the table of every endpoint, in sync order
+var tables = []table{
+	{name: "meetings", model: reflect.TypeOf(openf1go.Meeting{}), key: []string{"meeting_key"}},
+	{name: "sessions", model: reflect.TypeOf(openf1go.Session{}), key: []string{"session_key"}},
+	{name: "drivers", model: reflect.TypeOf(openf1go.Driver{}), key: []string{"session_key", "driver_number"}},
+	{name: "laps", model: reflect.TypeOf(openf1go.Lap{}), key: []string{"session_key", "driver_number", "lap_number"}},
+	{name: "stints", model: reflect.TypeOf(openf1go.Stint{}), key: []string{"session_key", "driver_number", "stint_number"}},
+	{name: "pit", model: reflect.TypeOf(openf1go.Pit{}), key: []string{"session_key", "driver_number", "lap_number"}, dated: true},
+	{name: "position", model: reflect.TypeOf(openf1go.Position{}), key: []string{"session_key", "driver_number", "date"}, dated: true},
+	{name: "intervals", model: reflect.TypeOf(openf1go.Interval{}), key: []string{"session_key", "driver_number", "date"}, dated: true},
+	{name: "race_control", model: reflect.TypeOf(openf1go.RaceControl{}), key: []string{"session_key", "date", "category", "message"}, dated: true},
+	{name: "team_radio", model: reflect.TypeOf(openf1go.TeamRadio{}), key: []string{"session_key", "driver_number", "date"}, dated: true},
+	{name: "weather", model: reflect.TypeOf(openf1go.Weather{}), key: []string{"session_key", "date"}, dated: true},
+	{name: "car_data", model: reflect.TypeOf(openf1go.CarData{}), key: []string{"session_key", "driver_number", "date"}, dated: true, perDriver: true},
+	{name: "location", model: reflect.TypeOf(openf1go.Location{}), key: []string{"session_key", "driver_number", "date"}, dated: true, perDriver: true},
+}
+
+// stringDates lists string columns the models keep as sent by the API that hold dates, such as the date of a
+// team radio message. They are stored with timeLayout like time.Time columns so they sort and compare alike.
+var stringDates = map[string]bool{
+	"date": true,
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Store is a local SQLite database of API data
+type Store struct {
+	db *sql.DB // Underlying database
+}
+
+// Open opens the database at path, creating it and its schema when needed
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer, serialise access through one connection
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.createSchema(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB returns the underlying database for queries not covered by the typed helpers
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// createSchema creates the table of every endpoint and the sync state table
+func (s *Store) createSchema(ctx context.Context) error {
+	statements := []string{
+		`PRAGMA journal_mode = WAL`,
+		`CREATE TABLE IF NOT EXISTS sync_state (
+			session_key INTEGER NOT NULL,
+			endpoint TEXT NOT NULL,
+			synced_at TEXT NOT NULL,
+			complete INTEGER NOT NULL,
+			PRIMARY KEY (session_key, endpoint)
+		)`,
+	}
+
+	for _, t := range tables {
+		columns := []string{}
+		for _, c := range columnsOf(t.model) {
+			columns = append(columns, c.name+" "+c.sqlType())
+		}
+		columns = append(columns, "PRIMARY KEY ("+strings.Join(t.key, ", ")+")")
+
+		statements = append(statements, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", t.name, strings.Join(columns, ",\n\t")))
+
+		// Most queries select a session, and often a driver within it
+		switch {
+		case t.name == "meetings" || t.name == "sessions":
+		case t.hasColumn("driver_number"):
+			statements = append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_session ON %s (session_key, driver_number)", t.name, t.name))
+		default:
+			statements = append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_session ON %s (session_key)", t.name, t.name))
+		}
+	}
+	statements = append(statements,
+		`CREATE INDEX IF NOT EXISTS meetings_year ON meetings (year)`,
+		`CREATE INDEX IF NOT EXISTS sessions_year ON sessions (year)`,
+	)
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("creating schema: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// column describes a model field stored in a table column
+type column struct {
+	index int          // Index of the field in the model
+	name  string       // Name of the column, the name of the field in the API
+	typ   reflect.Type // Type of the field
+}
+
+// columnsOf returns the columns of a model, one per field the API returns
+func columnsOf(model reflect.Type) []column {
+	columns := []column{}
+	for i := 0; i < model.NumField(); i++ {
+		f := model.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		columns = append(columns, column{index: i, name: name, typ: f.Type})
+	}
+	return columns
+}
+
+// sqlType returns the SQLite type of the column
+func (c column) sqlType() string {
+	switch c.typ.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Bool:
+		return "INTEGER"
+	case reflect.Float64:
+		return "REAL"
+	}
+	// Dates, strings, and slices and raw values stored as JSON
+	return "TEXT"
+}
+
+// toSQL converts a model field into the value stored in the column
+func (c column) toSQL(v reflect.Value) (any, error) {
+	switch {
+	case c.typ == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t.UTC().Format(timeLayout), nil
+	case c.typ == rawMessageType:
+		if v.Len() == 0 || string(v.Bytes()) == "null" {
+			return nil, nil
+		}
+		return string(v.Bytes()), nil
+	case c.typ.Kind() == reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		data, err := json.Marshal(v.Interface())
+		return string(data), err
+	case c.typ.Kind() == reflect.Int || c.typ.Kind() == reflect.Int64:
+		return v.Int(), nil
+	case c.typ.Kind() == reflect.Float64:
+		return v.Float(), nil
+	case c.typ.Kind() == reflect.Bool:
+		return v.Bool(), nil
+	case c.typ.Kind() == reflect.String && stringDates[c.name]:
+		// Dates that do not parse are stored as sent
+		t, err := time.Parse(time.RFC3339Nano, v.String())
+		if err != nil {
+			return v.String(), nil
+		}
+		return t.UTC().Format(timeLayout), nil
+	case c.typ.Kind() == reflect.String:
+		return v.String(), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, c.typ)
+}
+
+// fromSQL sets a model field from the value read from the column
+func (c column) fromSQL(v reflect.Value, value any) error {
+	if value == nil {
+		return nil
+	}
+
+	switch {
+	case c.typ == timeType:
+		t, err := time.Parse(time.RFC3339Nano, asString(value))
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+	case c.typ == rawMessageType:
+		v.SetBytes([]byte(asString(value)))
+	case c.typ.Kind() == reflect.Slice:
+		return json.Unmarshal([]byte(asString(value)), v.Addr().Interface())
+	case c.typ.Kind() == reflect.Int || c.typ.Kind() == reflect.Int64:
+		n, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("%w: %T in %s", ErrUnsupportedType, value, c.name)
+		}
+		v.SetInt(n)
+	case c.typ.Kind() == reflect.Float64:
+		switch f := value.(type) {
+		case float64:
+			v.SetFloat(f)
+		case int64:
+			v.SetFloat(float64(f))
+		}
+	case c.typ.Kind() == reflect.Bool:
+		n, _ := value.(int64)
+		v.SetBool(n != 0)
+	case c.typ.Kind() == reflect.String:
+		v.SetString(asString(value))
+	}
+
+	return nil
+}
+
+// asString returns a text value read from SQLite, which may come back as a string or bytes
+func asString(value any) string {
+	switch s := value.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// insert stores records decoded from the API, replacing records with the same key
+func (s *Store) insert(ctx context.Context, t table, records reflect.Value) error {
+	if records.Len() == 0 {
+		return nil
+	}
+
+	columns := columnsOf(t.model)
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	statement := fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)",
+		t.name, strings.Join(names, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	values := make([]any, len(columns))
+	for i := 0; i < records.Len(); i++ {
+		record := records.Index(i)
+		for j, c := range columns {
+			if values[j], err = c.toSQL(record.Field(c.index)); err != nil {
+				return err
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return fmt.Errorf("inserting into %s: %w", t.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// hasColumn reports whether the table has a column with the given name
+func (t table) hasColumn(name string) bool {
+	for _, c := range columnsOf(t.model) {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tableOf returns the table of an endpoint
+func tableOf(name string) table {
+	for _, t := range tables {
+		if t.name == name {
+			return t
+		}
+	}
+	panic("storage: unknown table " + name)
+}
@@ -0,0 +1,277 @@
+package storage
+
+// Syncs meetings, sessions and session data from the API into the store, fetching only what is missing.
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// LiveMargin is how long after its scheduled end a session may still receive data.
+// Sessions are synced in full once, then only for newer records until this margin has passed.
+var LiveMargin = 2 * time.Hour
+
+// syncPadding widens the session window per driver endpoints are fetched in,
+// as cars report data before the start and after the end of a session
+const syncPadding = time.Hour
+
+// syncer paces the requests of a sync
+type syncer struct {
+	store  *Store           // Store records are written to
+	client *openf1go.Client // Client records are fetched with
+	ticker *time.Ticker     // Paces requests to the request interval of the download options
+	chunk  time.Duration    // Length of the time windows per driver endpoints are fetched in
+	now    time.Time        // Time the sync started
+}
+
+// SyncYear syncs the meetings and sessions of a year, then the data of every started session not yet complete.
+// Requests are spaced by opts.RequestInterval and car data and location are fetched in windows of opts.Chunk.
+// Zero options fall back to openf1go.DefaultDownloadOptions.
+func (s *Store) SyncYear(ctx context.Context, c *openf1go.Client, year int, opts openf1go.DownloadOptions) error {
+	sy, err := s.newSyncer(c, opts)
+	if err != nil {
+		return err
+	}
+	defer sy.ticker.Stop()
+
+	yearArgs := []openf1go.Arg{{Key: "year", Value: strconv.Itoa(year)}}
+	if err := sy.sync(ctx, tableOf("meetings"), yearArgs); err != nil {
+		return err
+	}
+	if err := sy.sync(ctx, tableOf("sessions"), yearArgs); err != nil {
+		return err
+	}
+
+	sessions, err := s.Sessions(ctx, year)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.DateStart.After(sy.now) {
+			continue
+		}
+		if err := sy.syncSession(ctx, session); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncSession syncs the data of a single session, fetching the session first when it is not stored yet.
+// Requests are spaced and windowed like SyncYear.
+func (s *Store) SyncSession(ctx context.Context, c *openf1go.Client, sessionKey int, opts openf1go.DownloadOptions) error {
+	if sessionKey == 0 {
+		return openf1go.ErrSessionKeyMissing
+	}
+
+	sy, err := s.newSyncer(c, opts)
+	if err != nil {
+		return err
+	}
+	defer sy.ticker.Stop()
+
+	session, err := s.Session(ctx, sessionKey)
+	if errors.Is(err, ErrNotFound) {
+		if err := sy.sync(ctx, tableOf("sessions"), sessionArgs(sessionKey)); err != nil {
+			return err
+		}
+		session, err = s.Session(ctx, sessionKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep the meeting alongside its session
+	if err := sy.sync(ctx, tableOf("meetings"), []openf1go.Arg{{Key: "meeting_key", Value: strconv.Itoa(session.MeetingKey)}}); err != nil {
+		return err
+	}
+
+	return sy.syncSession(ctx, session)
+}
+
+// newSyncer creates a syncer fetching with the client, paced by the download options
+func (s *Store) newSyncer(c *openf1go.Client, opts openf1go.DownloadOptions) (*syncer, error) {
+	opts, err := opts.WithDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &syncer{store: s, client: c, ticker: time.NewTicker(opts.RequestInterval), chunk: opts.Chunk, now: time.Now()}, nil
+}
+
+// syncSession syncs every endpoint of a session that is not complete yet.
+// Dated endpoints only fetch records newer than the latest stored one, other endpoints are fetched again in full.
+func (sy *syncer) syncSession(ctx context.Context, session openf1go.Session) error {
+	// A session is complete once no more data can arrive
+	complete := !session.DateEnd.IsZero() && sy.now.After(session.DateEnd.Add(LiveMargin))
+
+	for _, t := range tables {
+		if t.name == "meetings" || t.name == "sessions" {
+			continue
+		}
+
+		done, err := sy.store.isComplete(ctx, session.SessionKey, t.name)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+
+		drivers := []int{0}
+		if t.perDriver {
+			if drivers, err = sy.store.driverNumbers(ctx, session.SessionKey); err != nil {
+				return err
+			}
+		}
+
+		for _, driver := range drivers {
+			args := sessionArgs(session.SessionKey)
+			if driver != 0 {
+				args = append(args, openf1go.Arg{Key: "driver_number", Value: strconv.Itoa(driver)})
+			}
+
+			// Only fetch records from the latest stored date on, which is fetched again as more records may share it
+			latest := ""
+			if t.dated {
+				if latest, err = sy.store.latestDate(ctx, t, session.SessionKey, driver); err != nil {
+					return err
+				}
+			}
+
+			if t.perDriver && !session.DateStart.IsZero() {
+				err = sy.syncChunked(ctx, t, args, session, latest)
+			} else {
+				if latest != "" {
+					args = append(args, openf1go.Arg{Key: "date>=", Value: latest})
+				}
+				err = sy.sync(ctx, t, args)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := sy.store.markSynced(ctx, session.SessionKey, t.name, complete); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sync fetches the records of a table matching the args and stores them
+func (sy *syncer) sync(ctx context.Context, t table, args []openf1go.Arg) error {
+	select {
+	case <-sy.ticker.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	resp, err := sy.client.Raw(ctx, t.name, args)
+	if err != nil {
+		return err
+	}
+
+	records := reflect.New(reflect.SliceOf(t.model))
+	if err := json.Unmarshal(resp, records.Interface()); err != nil {
+		return err
+	}
+
+	return sy.store.insert(ctx, t, records.Elem())
+}
+
+// syncChunked fetches the records of a per driver table in time windows across the session,
+// starting from the latest stored date when there is one
+func (sy *syncer) syncChunked(ctx context.Context, t table, args []openf1go.Arg, session openf1go.Session, latest string) error {
+	from := session.DateStart.Add(-syncPadding)
+	if latest != "" {
+		date, err := time.Parse(time.RFC3339Nano, latest)
+		if err != nil {
+			return err
+		}
+		from = date
+	}
+
+	// No data is recorded after now, so windows of a session still running stop there
+	end := session.DateEnd.Add(syncPadding)
+	if session.DateEnd.IsZero() || end.After(sy.now) {
+		end = sy.now
+	}
+
+	for ; !from.After(end); from = from.Add(sy.chunk) {
+		window := append(args[:len(args):len(args)],
+			openf1go.Arg{Key: "date>=", Value: from.UTC().Format(timeLayout)},
+			openf1go.Arg{Key: "date<", Value: from.Add(sy.chunk).UTC().Format(timeLayout)},
+		)
+		if err := sy.sync(ctx, t, window); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sessionArgs returns the args selecting a session
+func sessionArgs(sessionKey int) []openf1go.Arg {
+	return []openf1go.Arg{{Key: "session_key", Value: strconv.Itoa(sessionKey)}}
+}
+
+// isComplete reports whether an endpoint of a session was synced after the session could receive data
+func (s *Store) isComplete(ctx context.Context, sessionKey int, endpoint string) (bool, error) {
+	var complete bool
+	err := s.db.QueryRowContext(ctx, `SELECT complete FROM sync_state WHERE session_key = ? AND endpoint = ?`, sessionKey, endpoint).Scan(&complete)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return complete, err
+}
+
+// markSynced records that an endpoint of a session was synced
+func (s *Store) markSynced(ctx context.Context, sessionKey int, endpoint string, complete bool) error {
+	_, err := s.db.ExecContext(ctx, `INSERT OR REPLACE INTO sync_state (session_key, endpoint, synced_at, complete) VALUES (?, ?, ?, ?)`,
+		sessionKey, endpoint, time.Now().UTC().Format(timeLayout), complete)
+	return err
+}
+
+// latestDate returns the latest stored date of a session, for a single driver when driverNumber is not zero
+func (s *Store) latestDate(ctx context.Context, t table, sessionKey, driverNumber int) (string, error) {
+	var latest sql.NullString
+
+	q := `SELECT MAX(date) FROM ` + t.name + ` WHERE session_key = ?`
+	args := []any{sessionKey}
+	if driverNumber != 0 {
+		q += ` AND driver_number = ?`
+		args = append(args, driverNumber)
+	}
+
+	err := s.db.QueryRowContext(ctx, q, args...).Scan(&latest)
+	return latest.String, err
+}
+
+// driverNumbers returns the numbers of the drivers stored for a session
+func (s *Store) driverNumbers(ctx context.Context, sessionKey int) ([]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT driver_number FROM drivers WHERE session_key = ? ORDER BY driver_number`, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := []int{}
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, n)
+	}
+
+	return numbers, rows.Err()
+}
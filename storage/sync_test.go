@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+// testOptions paces syncs against the fake server without waiting
+var testOptions = openf1go.DownloadOptions{RequestInterval: time.Millisecond, Chunk: time.Hour}
+
+// openTestStore opens a store in a temporary directory, closed when the test ends
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(filepath.Join(t.TempDir(), "f1.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSyncSession(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	if err := store.SyncSession(ctx, client, 9158, testOptions); err != nil {
+		t.Fatalf("SyncSession() returned %v", err)
+	}
+
+	q := Query{SessionKey: 9158}
+	tests := []struct {
+		name    string
+		count   func() (int, error)
+		records int
+	}{
+		{name: "drivers", count: func() (int, error) { r, err := store.Drivers(ctx, q); return len(r), err }, records: 3},
+		{name: "laps", count: func() (int, error) { r, err := store.Laps(ctx, q); return len(r), err }, records: 18},
+		{name: "car data", count: func() (int, error) { r, err := store.CarData(ctx, q); return len(r), err }, records: 80},
+		{name: "positions", count: func() (int, error) { r, err := store.Positions(ctx, q); return len(r), err }, records: 5},
+		{name: "race control", count: func() (int, error) { r, err := store.RaceControl(ctx, q); return len(r), err }, records: 8},
+		{name: "team radio", count: func() (int, error) { r, err := store.TeamRadio(ctx, q); return len(r), err }, records: 2},
+		{name: "driver laps", count: func() (int, error) {
+			r, err := store.Laps(ctx, Query{SessionKey: 9158, DriverNumber: 44})
+			return len(r), err
+		}, records: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.count()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.records {
+				t.Errorf("stored %d records, want %d", got, tt.records)
+			}
+		})
+	}
+
+	// The session ended long ago, so a second sync only refreshes its meeting
+	before := server.Requests()
+	if err := store.SyncSession(ctx, client, 9158, testOptions); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.Requests() - before; got != 1 {
+		t.Errorf("second SyncSession() made %d requests, want 1", got)
+	}
+}
+
+func TestSyncSessionLive(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	// A session still running
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	session := openf1go.Session{SessionKey: 9999, MeetingKey: 1219, DateStart: start, DateEnd: start.Add(2 * time.Hour), Year: start.Year()}
+	if err := server.AddRecords("sessions", session); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.AddRecords("drivers", openf1go.Driver{SessionKey: 9999, MeetingKey: 1219, DriverNumber: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	positions := []openf1go.Position{
+		{SessionKey: 9999, MeetingKey: 1219, DriverNumber: 1, Position: 1, Date: start.Add(time.Minute)},
+		{SessionKey: 9999, MeetingKey: 1219, DriverNumber: 1, Position: 2, Date: start.Add(2 * time.Minute)},
+		{SessionKey: 9999, MeetingKey: 1219, DriverNumber: 1, Position: 1, Date: start.Add(3 * time.Minute)},
+		// Published after the previous sync with the same date as the latest stored record
+		{SessionKey: 9999, MeetingKey: 1219, DriverNumber: 44, Position: 2, Date: start.Add(3 * time.Minute)},
+	}
+
+	// Each sync stores the records added since the previous one without duplicating earlier ones
+	for i, position := range positions {
+		if err := server.AddRecords("position", position); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SyncSession(ctx, client, 9999, testOptions); err != nil {
+			t.Fatalf("SyncSession() returned %v", err)
+		}
+
+		stored, err := store.Positions(ctx, Query{SessionKey: 9999})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stored) != i+1 || !stored[len(stored)-1].Date.Equal(position.Date) {
+			t.Fatalf("after sync %d stored %+v", i+1, stored)
+		}
+	}
+}
+
+func TestSyncSessionOptions(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		opts openf1go.DownloadOptions
+		err  error
+	}{
+		{name: "short windows", opts: openf1go.DownloadOptions{RequestInterval: time.Millisecond, Chunk: 10 * time.Minute}},
+		{name: "zero chunk", opts: openf1go.DownloadOptions{RequestInterval: time.Millisecond}},
+		{name: "negative interval", opts: openf1go.DownloadOptions{RequestInterval: -time.Second}, err: openf1go.ErrInvalidDownloadOptions},
+		{name: "negative chunk", opts: openf1go.DownloadOptions{RequestInterval: time.Millisecond, Chunk: -time.Hour}, err: openf1go.ErrInvalidDownloadOptions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := openTestStore(t)
+
+			if err := store.SyncSession(ctx, client, 9158, tt.opts); !errors.Is(err, tt.err) {
+				t.Fatalf("SyncSession() returned %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			// Windows neither miss nor duplicate samples
+			for name, count := range map[string]func() (int, error){
+				"car data":  func() (int, error) { r, err := store.CarData(ctx, Query{SessionKey: 9158}); return len(r), err },
+				"locations": func() (int, error) { r, err := store.Locations(ctx, Query{SessionKey: 9158}); return len(r), err },
+			} {
+				got, err := count()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != 80 {
+					t.Errorf("stored %d %s samples, want 80", got, name)
+				}
+			}
+		})
+	}
+}
+
+func TestTeamRadioDates(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	if err := store.SyncSession(ctx, server.Client(), 9158, testOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dates are stored like those of every other table whatever the precision and zone sent
+	radio := openf1go.TeamRadio{SessionKey: 9158, MeetingKey: 1219, DriverNumber: 1, Date: "2023-09-17T14:05:00.5+02:00"}
+	if err := store.insert(ctx, tableOf("team_radio"), reflect.ValueOf(openf1go.TeamRadioResponse{radio})); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		dates []string
+	}{
+		{
+			name:  "all",
+			query: Query{SessionKey: 9158},
+			dates: []string{"2023-09-17T12:03:30.000000Z", "2023-09-17T12:05:00.500000Z", "2023-09-17T12:09:40.000000Z"},
+		},
+		{
+			name:  "date bounds",
+			query: Query{SessionKey: 9158, From: time.Date(2023, 9, 17, 12, 5, 0, 0, time.UTC), To: time.Date(2023, 9, 17, 12, 9, 40, 0, time.UTC)},
+			dates: []string{"2023-09-17T12:05:00.500000Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := store.TeamRadio(ctx, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dates := []string{}
+			for _, r := range stored {
+				dates = append(dates, r.Date)
+			}
+			if !reflect.DeepEqual(dates, tt.dates) {
+				t.Errorf("dates = %v, want %v", dates, tt.dates)
+			}
+		})
+	}
+
+	latest, err := store.latestDate(ctx, tableOf("team_radio"), 9158, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "2023-09-17T12:09:40.000000Z" {
+		t.Errorf("latestDate() = %s, want the latest message", latest)
+	}
+}
@@ -38,7 +38,7 @@ func (c *Client) GetTeamRadio(teamRadio TeamRadio) (TeamRadioResponse, error) {
 	}
 
 	// Make the HTTP GET request.
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +62,7 @@ func (c *Client) GetAllDriversLatestTeamRadio() (TeamRadioResponse, error) {
 	}
 
 	// Make the HTTP GET request.
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +86,7 @@ func (c *Client) GetDriverLatestTeamRadio(driver Driver) (TeamRadioResponse, err
 	}
 
 	// Make the HTTP GET request.
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
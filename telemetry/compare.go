@@ -0,0 +1,122 @@
+package telemetry
+
+// Compares the fastest laps of two drivers aligned by distance.
+
+import (
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/analytics"
+)
+
+// DefaultGridStep is the distance in meters between points of a comparison
+const DefaultGridStep = 5.0
+
+// Comparison represents two laps aligned by distance
+type Comparison struct {
+	LapA     openf1go.Lap       // Lap of the first driver
+	LapB     openf1go.Lap       // Lap of the second driver
+	Distance []float64          // Distance of each point in meters from the start of the lap
+	A        []Sample           // Samples of the first driver at each distance
+	B        []Sample           // Samples of the second driver at each distance
+	Delta    []float64          // Time of B minus time of A in seconds at each distance, positive when A is ahead
+	Corners  []CornerComparison // Comparison of each corner detected on the first driver's lap
+}
+
+// CornerComparison represents how two drivers took the same corner
+type CornerComparison struct {
+	Distance       float64 // Distance of the corner apex on the first driver's lap in meters
+	MinSpeedA      float64 // Minimum speed of the first driver in km/h
+	MinSpeedB      float64 // Minimum speed of the second driver in km/h
+	BrakePointA    float64 // Distance where the first driver started braking, only set when HasBrakePointA
+	BrakePointB    float64 // Distance where the second driver started braking, only set when HasBrakePointB
+	ThrottlePointA float64 // Distance where the first driver was back on full throttle, only set when HasThrottleA
+	ThrottlePointB float64 // Distance where the second driver was back on full throttle, only set when HasThrottleB
+	HasBrakePointA bool    // Indicates if the first driver's braking point was found
+	HasBrakePointB bool    // Indicates if the second driver's braking point was found
+	HasThrottleA   bool    // Indicates if the first driver's full throttle point was found
+	HasThrottleB   bool    // Indicates if the second driver's full throttle point was found
+}
+
+// Compare selects the fastest lap of each driver in the session, fetches their car data and compares them
+func Compare(c *openf1go.Client, session openf1go.Session, a, b openf1go.Driver) (Comparison, error) {
+	// Validate the session and drivers
+	if session.SessionKey == 0 {
+		return Comparison{}, openf1go.ErrSessionKeyMissing
+	}
+	if a.DriverNumber == 0 || b.DriverNumber == 0 {
+		return Comparison{}, openf1go.ErrDriverNumberMissing
+	}
+
+	lapA, err := fastestLap(c, session, a)
+	if err != nil {
+		return Comparison{}, err
+	}
+	lapB, err := fastestLap(c, session, b)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	samplesA, err := LapSamples(c, lapA)
+	if err != nil {
+		return Comparison{}, err
+	}
+	samplesB, err := LapSamples(c, lapB)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	return CompareLaps(lapA, lapB, samplesA, samplesB, DefaultGridStep), nil
+}
+
+// fastestLap returns the fastest clean lap of a driver in a session
+func fastestLap(c *openf1go.Client, session openf1go.Session, driver openf1go.Driver) (openf1go.Lap, error) {
+	laps, err := c.GetLaps(openf1go.Lap{SessionKey: session.SessionKey, DriverNumber: driver.DriverNumber})
+	if err != nil {
+		return openf1go.Lap{}, err
+	}
+
+	lap, ok := analytics.FastestLap(laps)
+	if !ok {
+		return openf1go.Lap{}, ErrNoValidLap
+	}
+
+	return lap, nil
+}
+
+// CompareLaps aligns the samples of two laps on a common distance grid and compares them
+func CompareLaps(lapA, lapB openf1go.Lap, samplesA, samplesB []Sample, step float64) Comparison {
+	grid := CommonGrid(step, samplesA, samplesB)
+
+	comparison := Comparison{
+		LapA:     lapA,
+		LapB:     lapB,
+		Distance: grid,
+		A:        Resample(samplesA, grid),
+		B:        Resample(samplesB, grid),
+		Delta:    make([]float64, len(grid)),
+		Corners:  []CornerComparison{},
+	}
+
+	for i := range grid {
+		comparison.Delta[i] = comparison.B[i].Elapsed - comparison.A[i].Elapsed
+	}
+
+	opts := DefaultDetectionOptions()
+	for _, apex := range findApexes(comparison.A, opts) {
+		a, b := cornerPoints(comparison.A, apex, opts), cornerPoints(comparison.B, apex, opts)
+		comparison.Corners = append(comparison.Corners, CornerComparison{
+			Distance:       grid[apex],
+			MinSpeedA:      a.minSpeed,
+			MinSpeedB:      b.minSpeed,
+			BrakePointA:    a.brakePoint,
+			BrakePointB:    b.brakePoint,
+			ThrottlePointA: a.throttlePoint,
+			ThrottlePointB: b.throttlePoint,
+			HasBrakePointA: a.braked,
+			HasBrakePointB: b.braked,
+			HasThrottleA:   a.throttled,
+			HasThrottleB:   b.throttled,
+		})
+	}
+
+	return comparison
+}
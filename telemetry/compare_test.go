@@ -0,0 +1,116 @@
+package telemetry
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+// steadyData returns car data of a driver at a constant speed, one sample a second for the given seconds
+func steadyData(driverNumber int, start time.Time, speed, seconds int) openf1go.CarDataResponse {
+	data := openf1go.CarDataResponse{}
+	for s := 0; s <= seconds; s++ {
+		data = append(data, openf1go.CarData{SessionKey: 9158, DriverNumber: driverNumber, Date: start.Add(time.Duration(s) * time.Second), Speed: speed, Throttle: 100})
+	}
+	return data
+}
+
+// checkDelta verifies a comparison of a lap at 72 km/h against one at 36 km/h over 200 meters.
+// The slower lap loses a second every 20 meters.
+func checkDelta(t *testing.T, c Comparison) {
+	t.Helper()
+
+	if len(c.Distance) != 41 || len(c.A) != 41 || len(c.B) != 41 || len(c.Delta) != 41 {
+		t.Fatalf("comparison has %d distances, %d and %d samples and %d deltas, want 41 of each", len(c.Distance), len(c.A), len(c.B), len(c.Delta))
+	}
+	for i, d := range c.Distance {
+		if want := float64(i) * DefaultGridStep; d != want {
+			t.Fatalf("distance %d = %v, want %v", i, d, want)
+		}
+		if want := d / 20; math.Abs(c.Delta[i]-want) > 1e-9 {
+			t.Errorf("delta at %vm = %v, want %v", d, c.Delta[i], want)
+		}
+		if c.A[i].Speed != 72 || c.B[i].Speed != 36 {
+			t.Errorf("speeds at %vm = %v and %v, want 72 and 36", d, c.A[i].Speed, c.B[i].Speed)
+		}
+	}
+	if len(c.Corners) != 0 {
+		t.Errorf("straight laps have corners %+v", c.Corners)
+	}
+}
+
+func TestCompareLaps(t *testing.T) {
+	lapA := openf1go.Lap{DriverNumber: 1, LapNumber: 2}
+	lapB := openf1go.Lap{DriverNumber: 44, LapNumber: 2}
+	a := FromCarData(steadyData(1, lapStart, 72, 10))
+	b := FromCarData(steadyData(44, lapStart, 36, 20))
+
+	c := CompareLaps(lapA, lapB, a, b, DefaultGridStep)
+	if c.LapA.DriverNumber != 1 || c.LapB.DriverNumber != 44 {
+		t.Errorf("CompareLaps() compared drivers %d and %d, want 1 and 44", c.LapA.DriverNumber, c.LapB.DriverNumber)
+	}
+	checkDelta(t, c)
+
+	// The grid stops at the shorter lap
+	short := CompareLaps(lapA, lapB, a, FromCarData(steadyData(44, lapStart, 36, 10)), DefaultGridStep)
+	if n := len(short.Distance); n != 21 || short.Distance[n-1] != 100 {
+		t.Errorf("CompareLaps() with a 100 meter lap has distances %v", short.Distance)
+	}
+
+	empty := CompareLaps(lapA, lapB, a, nil, DefaultGridStep)
+	if len(empty.Distance) != 0 || len(empty.Delta) != 0 || len(empty.Corners) != 0 {
+		t.Errorf("CompareLaps() without samples = %+v", empty)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	// Car data over the fastest clean lap of each driver, lap 4 of driver 1 and lap 6 of driver 44
+	records := []any{}
+	for _, d := range steadyData(1, time.Date(2023, 9, 17, 12, 5, 14, 728000000, time.UTC), 72, 10) {
+		records = append(records, d)
+	}
+	for _, d := range steadyData(44, time.Date(2023, 9, 17, 12, 8, 58, 862000000, time.UTC), 36, 20) {
+		records = append(records, d)
+	}
+	if err := server.AddRecords("car_data", records...); err != nil {
+		t.Fatal(err)
+	}
+
+	session := openf1go.Session{SessionKey: 9158}
+	c, err := Compare(client, session, openf1go.Driver{DriverNumber: 1}, openf1go.Driver{DriverNumber: 44})
+	if err != nil {
+		t.Fatalf("Compare() returned %v", err)
+	}
+	if c.LapA.LapNumber != 4 || c.LapB.LapNumber != 6 {
+		t.Errorf("Compare() compared laps %d and %d, want 4 and 6", c.LapA.LapNumber, c.LapB.LapNumber)
+	}
+	checkDelta(t, c)
+
+	tests := []struct {
+		name    string
+		session openf1go.Session
+		a, b    int
+		err     error
+	}{
+		{name: "no session", a: 1, b: 44, err: openf1go.ErrSessionKeyMissing},
+		{name: "no driver", session: session, a: 1, err: openf1go.ErrDriverNumberMissing},
+		{name: "driver without laps", session: session, a: 1, b: 63, err: ErrNoValidLap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compare(client, tt.session, openf1go.Driver{DriverNumber: tt.a}, openf1go.Driver{DriverNumber: tt.b})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Compare() returned %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
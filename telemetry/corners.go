@@ -0,0 +1,329 @@
+package telemetry
+
+// Detects corners, braking zones and full throttle sections in telemetry and maps corners onto circuit coordinates.
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DetectionOptions configures corner and braking zone detection
+type DetectionOptions struct {
+	MinSpeedDrop    float64 // Minimum speed drop in km/h from the preceding maximum for a minimum to count as a corner
+	MinCornerGap    float64 // Minimum distance in meters between two corners
+	ApexWindow      float64 // Distance in meters around an apex searched for the minimum speed of another lap
+	BrakeSearch     float64 // Distance in meters before an apex searched for the braking point
+	BrakeThreshold  float64 // Brake value at or above which the driver is considered braking
+	MinDeceleration float64 // Minimum average deceleration in m/s² for a braking zone
+	FullThrottle    float64 // Throttle percentage at or above which the driver is considered on full throttle
+}
+
+// DefaultDetectionOptions returns the thresholds used when none are provided
+func DefaultDetectionOptions() DetectionOptions {
+	return DetectionOptions{
+		MinSpeedDrop:    20,
+		MinCornerGap:    100,
+		ApexWindow:      150,
+		BrakeSearch:     400,
+		BrakeThreshold:  1,
+		MinDeceleration: 5,
+		FullThrottle:    95,
+	}
+}
+
+// Corner represents a corner detected from a speed minimum
+type Corner struct {
+	Number        int     // Corner number in order of distance, starting at 1
+	Distance      float64 // Distance of the apex in meters
+	MinSpeed      float64 // Minimum speed in km/h
+	EntrySpeed    float64 // Highest speed before the corner in km/h
+	BrakePoint    float64 // Distance where braking started, only set when HasBrakePoint
+	ThrottlePoint float64 // Distance where full throttle was reached again, only set when HasThrottle
+	HasBrakePoint bool    // Indicates if braking was found before the apex, false when taken without braking
+	HasThrottle   bool    // Indicates if full throttle was reached again after the apex
+}
+
+// BrakingZone represents a continuous period of braking
+type BrakingZone struct {
+	Start        float64   // Distance in meters where braking started
+	End          float64   // Distance in meters where braking ended
+	StartDate    time.Time // Time braking started
+	EndDate      time.Time // Time braking ended
+	EntrySpeed   float64   // Speed in km/h when braking started
+	ExitSpeed    float64   // Speed in km/h when braking ended
+	Deceleration float64   // Average deceleration in m/s²
+}
+
+// DetectCorners returns the corners of a lap ordered by distance
+func DetectCorners(samples []Sample, opts DetectionOptions) []Corner {
+	corners := []Corner{}
+
+	for _, apex := range findApexes(samples, opts) {
+		// The entry speed is the highest speed since the previous corner
+		from := 0
+		if len(corners) > 0 {
+			from = sort.Search(len(samples), func(i int) bool { return samples[i].Distance >= corners[len(corners)-1].Distance })
+		}
+		peak := 0.0
+		for i := from; i <= apex; i++ {
+			peak = math.Max(peak, samples[i].Speed)
+		}
+
+		points := cornerPoints(samples, apex, opts)
+		corners = append(corners, Corner{
+			Number:        len(corners) + 1,
+			Distance:      samples[apex].Distance,
+			MinSpeed:      points.minSpeed,
+			EntrySpeed:    peak,
+			BrakePoint:    points.brakePoint,
+			ThrottlePoint: points.throttlePoint,
+			HasBrakePoint: points.braked,
+			HasThrottle:   points.throttled,
+		})
+	}
+
+	return corners
+}
+
+// findApexes returns the indexes of speed minima preceded by a large enough drop in speed
+func findApexes(samples []Sample, opts DetectionOptions) []int {
+	apexes := []int{}
+	peak := 0.0
+
+	for i := 1; i+1 < len(samples); i++ {
+		speed := samples[i].Speed
+		if speed > peak {
+			peak = speed
+		}
+		if speed > samples[i-1].Speed || speed >= samples[i+1].Speed || peak-speed < opts.MinSpeedDrop {
+			continue
+		}
+
+		// Merge minima that are too close together, keeping the slowest
+		if n := len(apexes); n > 0 && samples[i].Distance-samples[apexes[n-1]].Distance < opts.MinCornerGap {
+			if speed < samples[apexes[n-1]].Speed {
+				apexes[n-1] = i
+			}
+			continue
+		}
+
+		apexes = append(apexes, i)
+		peak = speed
+	}
+
+	return apexes
+}
+
+// cornerPoint holds the minimum speed, braking point and full throttle point around an apex
+type cornerPoint struct {
+	minSpeed      float64 // Minimum speed in km/h
+	brakePoint    float64 // Distance where braking started
+	throttlePoint float64 // Distance where full throttle was reached again
+	braked        bool    // Indicates if brakePoint was found
+	throttled     bool    // Indicates if throttlePoint was found
+}
+
+// cornerPoints returns the minimum speed, braking point and full throttle point around an apex index
+func cornerPoints(samples []Sample, apex int, opts DetectionOptions) cornerPoint {
+	points := cornerPoint{}
+	if apex >= len(samples) {
+		return points
+	}
+	center := samples[apex].Distance
+
+	// The minimum speed may be at a slightly different distance on another lap
+	low := apex
+	for i := apex; i >= 0 && center-samples[i].Distance <= opts.ApexWindow; i-- {
+		if samples[i].Speed < samples[low].Speed {
+			low = i
+		}
+	}
+	for i := apex; i < len(samples) && samples[i].Distance-center <= opts.ApexWindow; i++ {
+		if samples[i].Speed < samples[low].Speed {
+			low = i
+		}
+	}
+	points.minSpeed = samples[low].Speed
+
+	// Walk back from the minimum to the start of the braking phase
+	for i := low; i >= 0 && samples[low].Distance-samples[i].Distance <= opts.BrakeSearch; i-- {
+		if samples[i].Brake >= opts.BrakeThreshold && (i == 0 || samples[i-1].Brake < opts.BrakeThreshold) {
+			points.brakePoint, points.braked = samples[i].Distance, true
+			break
+		}
+	}
+
+	// Walk forward from the minimum to the first full throttle sample
+	for i := low; i < len(samples); i++ {
+		if samples[i].Throttle >= opts.FullThrottle {
+			points.throttlePoint, points.throttled = samples[i].Distance, true
+			break
+		}
+	}
+
+	return points
+}
+
+// DetectBrakingZones returns the braking zones of a lap ordered by distance.
+// A braking zone starts at the onset of brake pressure and is kept when the car decelerates fast enough.
+func DetectBrakingZones(samples []Sample, opts DetectionOptions) []BrakingZone {
+	zones := []BrakingZone{}
+
+	for i := 0; i < len(samples); i++ {
+		if samples[i].Brake < opts.BrakeThreshold {
+			continue
+		}
+
+		// Find the end of the continuous braking run
+		j := i
+		for j+1 < len(samples) && samples[j+1].Brake >= opts.BrakeThreshold {
+			j++
+		}
+
+		start, end := samples[i], samples[j]
+		zone := BrakingZone{
+			Start:      start.Distance,
+			End:        end.Distance,
+			StartDate:  start.Date,
+			EndDate:    end.Date,
+			EntrySpeed: start.Speed,
+			ExitSpeed:  end.Speed,
+		}
+		if dt := end.Elapsed - start.Elapsed; dt > 0 {
+			zone.Deceleration = (start.Speed - end.Speed) / 3.6 / dt
+		}
+		if zone.Deceleration >= opts.MinDeceleration {
+			zones = append(zones, zone)
+		}
+
+		i = j
+	}
+
+	return zones
+}
+
+// FullThrottlePercentage returns the share of the lap distance driven on full throttle as a percentage
+func FullThrottlePercentage(samples []Sample, opts DetectionOptions) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	var full float64
+	for i := 1; i < len(samples); i++ {
+		if samples[i-1].Throttle >= opts.FullThrottle {
+			full += samples[i].Distance - samples[i-1].Distance
+		}
+	}
+
+	total := samples[len(samples)-1].Distance - samples[0].Distance
+	if total <= 0 {
+		return 0
+	}
+
+	return full / total * 100
+}
+
+// MapCorner represents a corner positioned on the circuit
+type MapCorner struct {
+	Number   int     // Corner number in order of distance, starting at 1
+	Distance float64 // Distance of the apex from the start of the reference lap in meters
+	X        float64 // X-coordinate of the apex
+	Y        float64 // Y-coordinate of the apex
+	MinSpeed float64 // Minimum speed on the reference lap in km/h
+}
+
+// CornerMap represents the corners of a circuit derived from a reference lap
+type CornerMap struct {
+	CircuitKey int         // Unique identifier for the circuit
+	Corners    []MapCorner // Corners ordered by distance
+}
+
+// CornerPass represents a driver passing a corner of a CornerMap
+type CornerPass struct {
+	Corner   MapCorner // The corner passed
+	Date     time.Time // Time of the slowest sample near the corner
+	MinSpeed float64   // Minimum speed near the corner in km/h
+}
+
+// toSamples converts merged telemetry into samples with the distance travelled
+func toSamples(telemetry []TelemetrySample) []Sample {
+	samples := make([]Sample, len(telemetry))
+	for i, t := range telemetry {
+		samples[i] = Sample{
+			Date:         t.Date,
+			DriverNumber: t.DriverNumber,
+			Speed:        float64(t.Speed),
+			Throttle:     float64(t.Throttle),
+			Brake:        float64(t.Brake),
+			RPM:          float64(t.RPM),
+			Gear:         t.Gear,
+			DRS:          t.DRS,
+		}
+	}
+	integrate(samples)
+	return samples
+}
+
+// BuildCornerMap detects the corners of a reference lap and positions them using its coordinates.
+// The telemetry must be ordered by time and cover a single lap.
+func BuildCornerMap(circuitKey int, lap []TelemetrySample, opts DetectionOptions) CornerMap {
+	samples := toSamples(lap)
+	m := CornerMap{CircuitKey: circuitKey, Corners: []MapCorner{}}
+
+	for _, apex := range findApexes(samples, opts) {
+		m.Corners = append(m.Corners, MapCorner{
+			Number:   len(m.Corners) + 1,
+			Distance: samples[apex].Distance,
+			X:        lap[apex].X,
+			Y:        lap[apex].Y,
+			MinSpeed: samples[apex].Speed,
+		})
+	}
+
+	return m
+}
+
+// Nearest returns the corner closest to the given coordinates and its distance in coordinate units
+func (m CornerMap) Nearest(x, y float64) (MapCorner, float64, bool) {
+	var nearest MapCorner
+	best := math.Inf(1)
+
+	for _, corner := range m.Corners {
+		if d := math.Hypot(corner.X-x, corner.Y-y); d < best {
+			nearest, best = corner, d
+		}
+	}
+
+	return nearest, best, len(m.Corners) > 0
+}
+
+// Match finds the slowest sample within radius coordinate units of each corner for telemetry of any driver.
+// Corners the driver did not pass within the radius are left out.
+func (m CornerMap) Match(telemetry []TelemetrySample, radius float64) []CornerPass {
+	passes := map[int]*CornerPass{}
+
+	for _, t := range telemetry {
+		corner, d, ok := m.Nearest(t.X, t.Y)
+		if !ok || d > radius {
+			continue
+		}
+
+		pass, seen := passes[corner.Number]
+		if !seen {
+			passes[corner.Number] = &CornerPass{Corner: corner, Date: t.Date, MinSpeed: float64(t.Speed)}
+			continue
+		}
+		if float64(t.Speed) < pass.MinSpeed {
+			pass.Date, pass.MinSpeed = t.Date, float64(t.Speed)
+		}
+	}
+
+	matched := make([]CornerPass, 0, len(passes))
+	for _, pass := range passes {
+		matched = append(matched, *pass)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Corner.Number < matched[j].Corner.Number })
+
+	return matched
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"math"
+	"testing"
+)
+
+// profile builds samples every 10m from functions of the distance
+func profile(length float64, speed, throttle, brake func(d float64) float64) []Sample {
+	samples := []Sample{}
+	for d := 0.0; d <= length; d += 10 {
+		samples = append(samples, Sample{Distance: d, Speed: speed(d), Throttle: throttle(d), Brake: brake(d)})
+	}
+	return samples
+}
+
+// vShape returns a speed trace falling linearly from 250 km/h at the start to a minimum at the apex and rising again
+func vShape(apex, minSpeed float64) func(d float64) float64 {
+	return func(d float64) float64 {
+		return minSpeed + math.Abs(d-apex)*(250-minSpeed)/apex
+	}
+}
+
+// pressed returns a pedal trace at 100% between from and to meters and 0% elsewhere
+func pressed(from, to float64) func(d float64) float64 {
+	return func(d float64) float64 {
+		if d >= from && d < to {
+			return 100
+		}
+		return 0
+	}
+}
+
+func TestDetectCorners(t *testing.T) {
+	opts := DefaultDetectionOptions()
+
+	tests := []struct {
+		name    string
+		samples []Sample
+		want    Corner
+		corners int
+	}{
+		{
+			name: "braking from the start of the trace",
+			samples: profile(500, vShape(200, 80),
+				pressed(300, math.Inf(1)), pressed(0, 150)),
+			want:    Corner{Number: 1, Distance: 200, MinSpeed: 80, EntrySpeed: 250, BrakePoint: 0, ThrottlePoint: 300, HasBrakePoint: true, HasThrottle: true},
+			corners: 1,
+		},
+		{
+			name: "braking before the apex",
+			samples: profile(500, vShape(200, 80),
+				pressed(250, math.Inf(1)), pressed(60, 180)),
+			want:    Corner{Number: 1, Distance: 200, MinSpeed: 80, EntrySpeed: 250, BrakePoint: 60, ThrottlePoint: 250, HasBrakePoint: true, HasThrottle: true},
+			corners: 1,
+		},
+		{
+			name: "lift without braking or full throttle",
+			samples: profile(500, vShape(200, 200),
+				func(d float64) float64 { return 80 }, pressed(0, 0)),
+			want:    Corner{Number: 1, Distance: 200, MinSpeed: 200, EntrySpeed: 250},
+			corners: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corners := DetectCorners(tt.samples, opts)
+			if len(corners) != tt.corners {
+				t.Fatalf("DetectCorners() returned %d corners, want %d: %+v", len(corners), tt.corners, corners)
+			}
+			if corners[0] != tt.want {
+				t.Errorf("corner = %+v, want %+v", corners[0], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package telemetry
+
+// Integrates car data speed over time to compute distance, splits samples into laps and resamples them onto a common distance grid.
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// Sample represents a single telemetry sample positioned along the track
+type Sample struct {
+	Date         time.Time // Timestamp of the sample
+	DriverNumber int       // Driver's unique number
+	Distance     float64   // Distance travelled in meters since the first sample
+	Elapsed      float64   // Time in seconds since the first sample
+	Speed        float64   // Speed of the car in km/h
+	Throttle     float64   // Throttle pressure percentage (0-100)
+	Brake        float64   // Brake pressure percentage (0-100)
+	RPM          float64   // Engine revolutions per minute
+	Gear         int       // Current gear of the car
+	DRS          int       // DRS status as reported by the API
+}
+
+// FromCarData converts car data of a single driver into samples ordered by time with the distance travelled.
+// Distance is integrated from speed with the trapezoidal rule.
+func FromCarData(data openf1go.CarDataResponse) []Sample {
+	sorted := append(openf1go.CarDataResponse{}, data...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	samples := make([]Sample, len(sorted))
+	for i, d := range sorted {
+		samples[i] = Sample{
+			Date:         d.Date,
+			DriverNumber: d.DriverNumber,
+			Speed:        float64(d.Speed),
+			Throttle:     float64(d.Throttle),
+			Brake:        float64(d.Brake),
+			RPM:          float64(d.Rpm),
+			Gear:         d.NGear,
+			DRS:          d.Drs,
+		}
+	}
+
+	integrate(samples)
+
+	return samples
+}
+
+// integrate fills in the elapsed time and distance of samples ordered by time
+func integrate(samples []Sample) {
+	for i := range samples {
+		if i == 0 {
+			samples[i].Distance, samples[i].Elapsed = 0, 0
+			continue
+		}
+		dt := samples[i].Date.Sub(samples[i-1].Date).Seconds()
+		samples[i].Elapsed = samples[i-1].Elapsed + dt
+		samples[i].Distance = samples[i-1].Distance + (samples[i-1].Speed+samples[i].Speed)/2/3.6*dt
+	}
+}
+
+// lapWindow returns the start and end time of a lap.
+// The end is the start of the next lap when known, otherwise the start plus the lap duration.
+func lapWindow(lap openf1go.Lap, next *openf1go.Lap) (time.Time, time.Time, bool) {
+	if lap.DateStart.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	if next != nil && !next.DateStart.IsZero() {
+		return lap.DateStart, next.DateStart, true
+	}
+	if lap.LapDuration > 0 {
+		return lap.DateStart, lap.DateStart.Add(time.Duration(lap.LapDuration * float64(time.Second))), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// SplitLaps splits car data into laps using Lap.DateStart, keyed by driver number and lap number.
+// Distance and elapsed time of each lap start from zero at the first sample of the lap.
+func SplitLaps(data openf1go.CarDataResponse, laps openf1go.LapsResponse) map[int]map[int][]Sample {
+	// Group samples and laps by driver
+	samplesByDriver := map[int]openf1go.CarDataResponse{}
+	for _, d := range data {
+		samplesByDriver[d.DriverNumber] = append(samplesByDriver[d.DriverNumber], d)
+	}
+	lapsByDriver := map[int]openf1go.LapsResponse{}
+	for _, lap := range laps {
+		lapsByDriver[lap.DriverNumber] = append(lapsByDriver[lap.DriverNumber], lap)
+	}
+
+	split := map[int]map[int][]Sample{}
+	for driverNumber, driverData := range samplesByDriver {
+		samples := FromCarData(driverData)
+		driverLaps := lapsByDriver[driverNumber]
+		sort.Slice(driverLaps, func(i, j int) bool { return driverLaps[i].LapNumber < driverLaps[j].LapNumber })
+
+		split[driverNumber] = map[int][]Sample{}
+		for i, lap := range driverLaps {
+			var next *openf1go.Lap
+			if i+1 < len(driverLaps) && driverLaps[i+1].LapNumber == lap.LapNumber+1 {
+				next = &driverLaps[i+1]
+			}
+
+			start, end, ok := lapWindow(lap, next)
+			if !ok {
+				continue
+			}
+
+			if lapSamples := window(samples, start, end); len(lapSamples) > 0 {
+				split[driverNumber][lap.LapNumber] = lapSamples
+			}
+		}
+	}
+
+	return split
+}
+
+// window returns a copy of the samples within [start, end) with distance and elapsed time restarting at zero
+func window(samples []Sample, start, end time.Time) []Sample {
+	from := sort.Search(len(samples), func(i int) bool { return !samples[i].Date.Before(start) })
+	to := sort.Search(len(samples), func(i int) bool { return !samples[i].Date.Before(end) })
+	if from >= to {
+		return nil
+	}
+
+	lap := append([]Sample{}, samples[from:to]...)
+	integrate(lap)
+
+	return lap
+}
+
+// CommonGrid returns distances from zero to the shortest of the traces in steps of the given size in meters
+func CommonGrid(step float64, traces ...[]Sample) []float64 {
+	if step <= 0 || len(traces) == 0 {
+		return nil
+	}
+
+	length := math.Inf(1)
+	for _, trace := range traces {
+		if len(trace) == 0 {
+			return nil
+		}
+		length = math.Min(length, trace[len(trace)-1].Distance)
+	}
+
+	grid := make([]float64, 0, int(length/step)+1)
+	for d := 0.0; d <= length; d += step {
+		grid = append(grid, d)
+	}
+
+	return grid
+}
+
+// Resample interpolates samples ordered by distance onto the given distances.
+// Continuous channels are interpolated linearly, gear and DRS take the value of the nearest preceding sample.
+func Resample(samples []Sample, grid []float64) []Sample {
+	resampled := make([]Sample, 0, len(grid))
+	if len(samples) == 0 {
+		return resampled
+	}
+
+	for _, d := range grid {
+		// Find the first sample at or beyond the requested distance
+		i := sort.Search(len(samples), func(i int) bool { return samples[i].Distance >= d })
+		switch {
+		case i == 0:
+			s := samples[0]
+			s.Distance = d
+			resampled = append(resampled, s)
+		case i == len(samples):
+			s := samples[len(samples)-1]
+			s.Distance = d
+			resampled = append(resampled, s)
+		case samples[i].Distance == d:
+			resampled = append(resampled, samples[i])
+		default:
+			resampled = append(resampled, interpolate(samples[i-1], samples[i], d))
+		}
+	}
+
+	return resampled
+}
+
+// interpolate returns the sample at distance d between samples a and b
+func interpolate(a, b Sample, d float64) Sample {
+	f := 0.0
+	if b.Distance > a.Distance {
+		f = (d - a.Distance) / (b.Distance - a.Distance)
+	}
+	lerp := func(x, y float64) float64 { return x + (y-x)*f }
+
+	return Sample{
+		Date:         a.Date.Add(time.Duration(float64(b.Date.Sub(a.Date)) * f)),
+		DriverNumber: a.DriverNumber,
+		Distance:     d,
+		Elapsed:      lerp(a.Elapsed, b.Elapsed),
+		Speed:        lerp(a.Speed, b.Speed),
+		Throttle:     lerp(a.Throttle, b.Throttle),
+		Brake:        lerp(a.Brake, b.Brake),
+		RPM:          lerp(a.RPM, b.RPM),
+		Gear:         a.Gear,
+		DRS:          a.DRS,
+	}
+}
+
+// LapSamples fetches the car data of a lap and returns it as samples starting at zero distance
+func LapSamples(c *openf1go.Client, lap openf1go.Lap) ([]Sample, error) {
+	start, end, ok := lapWindow(lap, nil)
+	if !ok {
+		return nil, ErrLapWindowUnknown
+	}
+
+	data, err := c.GetCarDataBetween(openf1go.CarData{SessionKey: lap.SessionKey, DriverNumber: lap.DriverNumber}, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromCarData(data), nil
+}
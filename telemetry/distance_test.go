@@ -0,0 +1,180 @@
+package telemetry
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+var lapStart = time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// after returns the time the given number of seconds after lapStart
+func after(seconds float64) time.Time {
+	return lapStart.Add(time.Duration(seconds * float64(time.Second)))
+}
+
+// carData returns car data of driver 1 at the given speeds, one sample per second
+func carData(speeds ...int) openf1go.CarDataResponse {
+	data := openf1go.CarDataResponse{}
+	for i, speed := range speeds {
+		data = append(data, openf1go.CarData{DriverNumber: 1, Date: after(float64(i)), Speed: speed, NGear: i + 1})
+	}
+	return data
+}
+
+func TestFromCarData(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      openf1go.CarDataResponse
+		distances []float64
+	}{
+		{name: "constant speed", data: carData(36, 36, 36), distances: []float64{0, 10, 20}},
+		{name: "accelerating", data: carData(0, 36, 72), distances: []float64{0, 5, 20}},
+		{name: "out of order", data: append(carData(36, 36)[1:], carData(36)...), distances: []float64{0, 10}},
+		{name: "no data", distances: []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := FromCarData(tt.data)
+
+			distances := []float64{}
+			for i, s := range samples {
+				distances = append(distances, s.Distance)
+				if s.Elapsed != float64(i) {
+					t.Errorf("sample %d elapsed = %v, want %d", i, s.Elapsed, i)
+				}
+			}
+			if !reflect.DeepEqual(distances, tt.distances) {
+				t.Errorf("distances = %v, want %v", distances, tt.distances)
+			}
+		})
+	}
+}
+
+func TestSplitLaps(t *testing.T) {
+	// 10 samples a second apart split into laps of 4 seconds
+	data := carData(36, 36, 36, 36, 36, 36, 36, 36, 36, 36)
+
+	tests := []struct {
+		name string
+		laps openf1go.LapsResponse
+		want map[int]int // Samples per lap number
+	}{
+		{
+			name: "next lap start",
+			laps: openf1go.LapsResponse{
+				{DriverNumber: 1, LapNumber: 1, DateStart: after(0)},
+				{DriverNumber: 1, LapNumber: 2, DateStart: after(4)},
+				{DriverNumber: 1, LapNumber: 3, DateStart: after(8)},
+			},
+			want: map[int]int{1: 4, 2: 4},
+		},
+		{
+			name: "lap duration",
+			laps: openf1go.LapsResponse{
+				{DriverNumber: 1, LapNumber: 2, DateStart: after(4), LapDuration: 4},
+				{DriverNumber: 1, LapNumber: 1, DateStart: after(0), LapDuration: 4},
+				{DriverNumber: 1, LapNumber: 3, DateStart: after(8), LapDuration: 4},
+			},
+			want: map[int]int{1: 4, 2: 4, 3: 2},
+		},
+		{
+			name: "lap without a start",
+			laps: openf1go.LapsResponse{
+				{DriverNumber: 1, LapNumber: 1, LapDuration: 4},
+				{DriverNumber: 1, LapNumber: 2, DateStart: after(4), LapDuration: 4},
+			},
+			want: map[int]int{2: 4},
+		},
+		{
+			name: "other driver",
+			laps: openf1go.LapsResponse{{DriverNumber: 44, LapNumber: 1, DateStart: after(0), LapDuration: 4}},
+			want: map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := SplitLaps(data, tt.laps)[1]
+
+			got := map[int]int{}
+			for lap, samples := range split {
+				got[lap] = len(samples)
+				if samples[0].Distance != 0 || samples[0].Elapsed != 0 {
+					t.Errorf("lap %d starts at %vm after %vs, want zero", lap, samples[0].Distance, samples[0].Elapsed)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("samples per lap = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonGrid(t *testing.T) {
+	short := FromCarData(carData(36, 36, 36))        // 20m
+	long := FromCarData(carData(36, 36, 36, 36, 36)) // 40m
+
+	tests := []struct {
+		name   string
+		step   float64
+		traces [][]Sample
+		want   []float64
+	}{
+		{name: "shortest trace", step: 5, traces: [][]Sample{long, short}, want: []float64{0, 5, 10, 15, 20}},
+		{name: "step beyond the end", step: 15, traces: [][]Sample{short}, want: []float64{0, 15}},
+		{name: "empty trace", step: 5, traces: [][]Sample{short, nil}},
+		{name: "no traces", step: 5},
+		{name: "invalid step", step: 0, traces: [][]Sample{short}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommonGrid(tt.step, tt.traces...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommonGrid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResample(t *testing.T) {
+	// 0m at 0 km/h, 5m at 36 km/h and 20m at 72 km/h, in gears 1, 2 and 3
+	samples := FromCarData(carData(0, 36, 72))
+
+	tests := []struct {
+		name     string
+		distance float64
+		speed    float64
+		gear     int
+		elapsed  float64
+	}{
+		{name: "first sample", distance: 0, speed: 0, gear: 1, elapsed: 0},
+		{name: "between samples", distance: 2.5, speed: 18, gear: 1, elapsed: 0.5},
+		{name: "on a sample", distance: 5, speed: 36, gear: 2, elapsed: 1},
+		{name: "later segment", distance: 12.5, speed: 54, gear: 2, elapsed: 1.5},
+		{name: "beyond the end", distance: 30, speed: 72, gear: 3, elapsed: 2},
+	}
+
+	grid := []float64{}
+	for _, tt := range tests {
+		grid = append(grid, tt.distance)
+	}
+	resampled := Resample(samples, grid)
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := resampled[i]
+			if s.Distance != tt.distance || math.Abs(s.Speed-tt.speed) > 1e-9 || s.Gear != tt.gear || math.Abs(s.Elapsed-tt.elapsed) > 1e-9 {
+				t.Errorf("Resample() at %vm = %+v, want %v km/h in gear %d after %vs", tt.distance, s, tt.speed, tt.gear, tt.elapsed)
+			}
+		})
+	}
+
+	if got := Resample(nil, grid); len(got) != 0 {
+		t.Errorf("Resample() without samples returned %d samples", len(got))
+	}
+}
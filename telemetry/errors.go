@@ -0,0 +1,8 @@
+package telemetry
+
+import "errors"
+
+var (
+	ErrLapWindowUnknown = errors.New("lap start or duration is missing")
+	ErrNoValidLap       = errors.New("no valid lap found for driver")
+)
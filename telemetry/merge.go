@@ -0,0 +1,108 @@
+package telemetry
+
+// Joins the independently sampled car data and location streams into unified telemetry samples.
+
+import (
+	"sort"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+// TelemetrySample represents car data combined with the position of the car at the same instant
+type TelemetrySample struct {
+	Date         time.Time // Timestamp of the car data sample
+	DriverNumber int       // Driver's unique number
+	X            float64   // X-coordinate interpolated from the location data
+	Y            float64   // Y-coordinate interpolated from the location data
+	Z            float64   // Z-coordinate interpolated from the location data
+	Speed        int       // Speed of the car in km/h
+	Throttle     int       // Throttle pressure percentage (0-100)
+	Brake        int       // Brake pressure percentage (0-100)
+	Gear         int       // Current gear of the car
+	RPM          int       // Engine revolutions per minute
+	DRS          int       // DRS status as reported by the API
+}
+
+// Merge joins car data and location data keyed by driver number.
+// Coordinates are linearly interpolated in time at each car data timestamp.
+// Car data outside the time range covered by the location data of the driver is dropped.
+func Merge(carData openf1go.CarDataResponse, locations openf1go.LocationResponse) map[int][]TelemetrySample {
+	// Group and sort locations by driver so they can be binary searched
+	locationsByDriver := map[int]openf1go.LocationResponse{}
+	for _, location := range locations {
+		locationsByDriver[location.DriverNumber] = append(locationsByDriver[location.DriverNumber], location)
+	}
+	for _, l := range locationsByDriver {
+		sort.SliceStable(l, func(i, j int) bool { return l[i].Date.Before(l[j].Date) })
+	}
+
+	sorted := append(openf1go.CarDataResponse{}, carData...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	merged := map[int][]TelemetrySample{}
+	for _, d := range sorted {
+		x, y, z, ok := locationAt(locationsByDriver[d.DriverNumber], d.Date)
+		if !ok {
+			continue
+		}
+
+		merged[d.DriverNumber] = append(merged[d.DriverNumber], TelemetrySample{
+			Date:         d.Date,
+			DriverNumber: d.DriverNumber,
+			X:            x,
+			Y:            y,
+			Z:            z,
+			Speed:        d.Speed,
+			Throttle:     d.Throttle,
+			Brake:        d.Brake,
+			Gear:         d.NGear,
+			RPM:          d.Rpm,
+			DRS:          d.Drs,
+		})
+	}
+
+	return merged
+}
+
+// locationAt interpolates the coordinates of a car at the given time from locations ordered by date
+func locationAt(locations openf1go.LocationResponse, at time.Time) (x, y, z float64, ok bool) {
+	i := sort.Search(len(locations), func(i int) bool { return !locations[i].Date.Before(at) })
+	if i == len(locations) {
+		return 0, 0, 0, false
+	}
+
+	b := locations[i]
+	if b.Date.Equal(at) {
+		return float64(b.X), float64(b.Y), float64(b.Z), true
+	}
+	if i == 0 {
+		return 0, 0, 0, false
+	}
+
+	a := locations[i-1]
+	f := float64(at.Sub(a.Date)) / float64(b.Date.Sub(a.Date))
+	lerp := func(p, q int) float64 { return float64(p) + float64(q-p)*f }
+
+	return lerp(a.X, b.X), lerp(a.Y, b.Y), lerp(a.Z, b.Z), true
+}
+
+// LapTelemetry fetches the car data and location data of a lap and merges them
+func LapTelemetry(c *openf1go.Client, lap openf1go.Lap) ([]TelemetrySample, error) {
+	start, end, ok := lapWindow(lap, nil)
+	if !ok {
+		return nil, ErrLapWindowUnknown
+	}
+
+	carData, err := c.GetCarDataBetween(openf1go.CarData{SessionKey: lap.SessionKey, DriverNumber: lap.DriverNumber}, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := c.GetLocationsBetween(openf1go.Location{SessionKey: lap.SessionKey, DriverNumber: lap.DriverNumber}, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return Merge(carData, locations)[lap.DriverNumber], nil
+}
@@ -0,0 +1,171 @@
+package telemetry
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+// locations returns locations of driver 1 with X moving 100 meters a second, missing the seconds left out
+func locations(seconds ...float64) openf1go.LocationResponse {
+	l := openf1go.LocationResponse{}
+	for _, s := range seconds {
+		l = append(l, openf1go.Location{DriverNumber: 1, Date: after(s), X: int(s * 100), Y: int(s * 10), Z: 2})
+	}
+	return l
+}
+
+func TestLocationAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		locations openf1go.LocationResponse
+		at        float64
+		x, y      float64
+		ok        bool
+	}{
+		{name: "on a sample", locations: locations(0, 1, 2), at: 1, x: 100, y: 10, ok: true},
+		{name: "between samples", locations: locations(0, 1, 2), at: 1.25, x: 125, y: 12.5, ok: true},
+		{name: "across a gap", locations: locations(0, 1, 4), at: 2.5, x: 250, y: 25, ok: true},
+		{name: "first sample", locations: locations(0, 1), at: 0, ok: true},
+		{name: "last sample", locations: locations(0, 1), at: 1, x: 100, y: 10, ok: true},
+		{name: "before the first sample", locations: locations(1, 2), at: 0.5},
+		{name: "after the last sample", locations: locations(1, 2), at: 2.5},
+		{name: "no locations", at: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, z, ok := locationAt(tt.locations, after(tt.at))
+			if ok != tt.ok {
+				t.Fatalf("locationAt() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(x-tt.x) > 1e-9 || math.Abs(y-tt.y) > 1e-9 || z != 2 {
+				t.Errorf("locationAt() = (%v, %v, %v), want (%v, %v, 2)", x, y, z, tt.x, tt.y)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	// Car data every half second, locations offset by a quarter second with a gap from 1.25 to 2.75
+	data := openf1go.CarDataResponse{}
+	for _, s := range []float64{2, 0, 0.5, 1, 1.5, 3, 2.5} {
+		data = append(data, openf1go.CarData{DriverNumber: 1, Date: after(s), Speed: int(s * 100), NGear: 3, Rpm: 10000, Throttle: 100, Drs: 8})
+	}
+	data = append(data, openf1go.CarData{DriverNumber: 44, Date: after(1), Speed: 200}) // No locations for the driver
+
+	merged := Merge(data, locations(2.75, 0.25, 0.75, 1.25))
+
+	if _, ok := merged[44]; ok {
+		t.Error("Merge() kept car data of a driver without locations")
+	}
+
+	want := []struct {
+		seconds float64
+		x       float64
+	}{
+		{seconds: 0.5, x: 50},
+		{seconds: 1, x: 100},
+		{seconds: 1.5, x: 150},
+		{seconds: 2, x: 200},
+		{seconds: 2.5, x: 250},
+	}
+	samples := merged[1]
+	if len(samples) != len(want) {
+		t.Fatalf("Merge() returned %d samples, want %d: %+v", len(samples), len(want), samples)
+	}
+	for i, w := range want {
+		s := samples[i]
+		if !s.Date.Equal(after(w.seconds)) || math.Abs(s.X-w.x) > 1e-9 {
+			t.Errorf("sample %d at %v has X %v, want X %v at %v", i, s.Date.Sub(lapStart), s.X, w.x, time.Duration(w.seconds*float64(time.Second)))
+		}
+		if s.Speed != int(w.seconds*100) || s.Gear != 3 || s.RPM != 10000 || s.Throttle != 100 || s.DRS != 8 || s.DriverNumber != 1 {
+			t.Errorf("sample %d = %+v, want the car data at %v", i, s, w.seconds)
+		}
+	}
+}
+
+func TestLapTelemetry(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	// Driver 63 has car data every second of a 5 second lap, but no location between seconds 1 and 4
+	for s := 0; s < 6; s++ {
+		if err := server.AddRecords("car_data", openf1go.CarData{SessionKey: 9158, DriverNumber: 63, Date: after(float64(s)), Speed: 100}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, s := range []int{0, 1, 4, 5} {
+		if err := server.AddRecords("location", openf1go.Location{SessionKey: 9158, DriverNumber: 63, Date: after(float64(s)), X: s * 100}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fixtureStart := time.Date(2023, 9, 17, 12, 3, 36, 304000000, time.UTC)
+
+	tests := []struct {
+		name    string
+		lap     openf1go.Lap
+		samples int
+		first   time.Time // Date of the first sample
+		x       []float64 // Expected X coordinates, nil to skip
+		err     error
+	}{
+		{
+			// The first car data sample comes before the first location and is dropped
+			name:    "fixture lap",
+			lap:     openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 3, DateStart: fixtureStart, LapDuration: 98.424},
+			samples: 39,
+			first:   fixtureStart.Add(270 * time.Millisecond),
+		},
+		{
+			name:    "gap in the locations",
+			lap:     openf1go.Lap{SessionKey: 9158, DriverNumber: 63, LapNumber: 2, DateStart: after(0), LapDuration: 5},
+			samples: 5,
+			first:   after(0),
+			x:       []float64{0, 100, 200, 300, 400},
+		},
+		{
+			// Locations end with the window, so car data after the last location in it is dropped
+			name:    "window ending in a gap",
+			lap:     openf1go.Lap{SessionKey: 9158, DriverNumber: 63, LapNumber: 2, DateStart: after(0), LapDuration: 3.5},
+			samples: 2,
+			first:   after(0),
+			x:       []float64{0, 100},
+		},
+		{
+			name: "lap without data",
+			lap:  openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 2, DateStart: fixtureStart.Add(-112245 * time.Millisecond), LapDuration: 112.245},
+		},
+		{name: "lap without a start", lap: openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 1}, err: ErrLapWindowUnknown},
+		{name: "lap without a duration", lap: openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 7, DateStart: fixtureStart}, err: ErrLapWindowUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples, err := LapTelemetry(client, tt.lap)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("LapTelemetry() returned %v, want %v", err, tt.err)
+			}
+			if len(samples) != tt.samples {
+				t.Fatalf("LapTelemetry() returned %d samples, want %d", len(samples), tt.samples)
+			}
+			if tt.samples > 0 && !samples[0].Date.Equal(tt.first) {
+				t.Errorf("first sample at %v, want %v", samples[0].Date, tt.first)
+			}
+			for i, x := range tt.x {
+				if math.Abs(samples[i].X-x) > 1e-9 {
+					t.Errorf("sample %d X = %v, want %v", i, samples[i].X, x)
+				}
+			}
+		})
+	}
+}
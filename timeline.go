@@ -0,0 +1,206 @@
+package openf1go
+
+// Reconstructs the classification of a session at any point in time from position, interval and lap data.
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Classification represents the running order at a point in time ordered by position
+type Classification []ClassificationEntry
+
+// ClassificationEntry represents a single driver's place in the running order
+type ClassificationEntry struct {
+	Position     int // Position of the driver
+	DriverNumber int // Driver's unique number
+	LapNumber    int // Lap the driver was on
+	GapToLeader  Gap // Gap to the race leader
+	Interval     Gap // Interval to the car ahead
+}
+
+// SessionTimeline holds the position, interval and lap history of a session sorted by time
+type SessionTimeline struct {
+	positions map[int][]Position // Positions per driver sorted by date
+	intervals map[int][]Interval // Intervals per driver sorted by date
+	laps      map[int][]Lap      // Laps per driver sorted by lap number
+	lapStarts map[int][]lapStart // Known lap starts per driver sorted by date
+}
+
+// lapStart is the time a driver started a lap
+type lapStart struct {
+	lapNumber int       // Lap started
+	date      time.Time // Start of the lap, zero for a first lap starting with the session
+}
+
+// NewSessionTimeline builds a SessionTimeline from the raw responses of a single session
+func NewSessionTimeline(positions PostionsResponse, intervals IntervalsResponse, laps LapsResponse) *SessionTimeline {
+	t := &SessionTimeline{
+		positions: map[int][]Position{},
+		intervals: map[int][]Interval{},
+		laps:      map[int][]Lap{},
+		lapStarts: map[int][]lapStart{},
+	}
+
+	// Group the records by driver
+	for _, position := range positions {
+		t.positions[position.DriverNumber] = append(t.positions[position.DriverNumber], position)
+	}
+	for _, interval := range intervals {
+		t.intervals[interval.DriverNumber] = append(t.intervals[interval.DriverNumber], interval)
+	}
+	for _, lap := range laps {
+		t.laps[lap.DriverNumber] = append(t.laps[lap.DriverNumber], lap)
+	}
+
+	// Sort each driver's records so they can be binary searched
+	for _, p := range t.positions {
+		sort.SliceStable(p, func(i, j int) bool { return p[i].Date.Before(p[j].Date) })
+	}
+	for _, in := range t.intervals {
+		sort.SliceStable(in, func(i, j int) bool { return in[i].Date.Before(in[j].Date) })
+	}
+	for driverNumber, l := range t.laps {
+		sort.SliceStable(l, func(i, j int) bool { return l[i].LapNumber < l[j].LapNumber })
+		t.lapStarts[driverNumber] = lapStarts(l)
+	}
+
+	return t
+}
+
+// lapStarts returns the start of every lap of a driver whose start is known, sorted by date.
+// Laps without a start time, common for lap 1 and laps restarted after a red flag, are taken to start when
+// the previous lap ended, or at the start of the session for the first lap, and are left out otherwise.
+func lapStarts(laps []Lap) []lapStart {
+	starts := []lapStart{}
+
+	for i, lap := range laps {
+		start := lap.DateStart
+		if start.IsZero() && i > 0 {
+			if laps[i-1].DateStart.IsZero() || laps[i-1].LapDuration <= 0 {
+				continue
+			}
+			start = laps[i-1].DateStart.Add(time.Duration(laps[i-1].LapDuration * float64(time.Second)))
+		}
+		starts = append(starts, lapStart{lapNumber: lap.LapNumber, date: start})
+	}
+
+	sort.SliceStable(starts, func(i, j int) bool { return starts[i].date.Before(starts[j].date) })
+	return starts
+}
+
+// GetSessionTimeline fetches positions, intervals and laps for a session and builds its SessionTimeline
+func (c *Client) GetSessionTimeline(session Session) (*SessionTimeline, error) {
+	// Validate that the session has a valid session key
+	if session.SessionKey == 0 {
+		return nil, ErrSessionKeyMissing
+	}
+
+	positions, err := c.GetPositions(Position{SessionKey: session.SessionKey})
+	if err != nil {
+		return nil, err
+	}
+
+	intervals, err := c.GetIntervals(Interval{SessionKey: session.SessionKey})
+	if err != nil {
+		return nil, err
+	}
+
+	laps, err := c.GetLaps(Lap{SessionKey: session.SessionKey})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSessionTimeline(positions, intervals, laps), nil
+}
+
+// StateAt returns the classification as it stood at the given time.
+// Drivers without a position record at or before t are left out.
+func (t *SessionTimeline) StateAt(at time.Time) Classification {
+	classification := Classification{}
+
+	for driverNumber, positions := range t.positions {
+		// Find the last position record at or before the requested time
+		i := sort.Search(len(positions), func(i int) bool { return positions[i].Date.After(at) })
+		if i == 0 {
+			continue
+		}
+
+		entry := ClassificationEntry{
+			Position:     positions[i-1].Position,
+			DriverNumber: driverNumber,
+			LapNumber:    t.lapAt(driverNumber, at),
+		}
+
+		// Attach the last interval record at or before the requested time
+		intervals := t.intervals[driverNumber]
+		if j := sort.Search(len(intervals), func(j int) bool { return intervals[j].Date.After(at) }); j > 0 {
+			entry.GapToLeader = intervals[j-1].GapToLeaderValue()
+			entry.Interval = intervals[j-1].IntervalValue()
+		}
+
+		classification = append(classification, entry)
+	}
+
+	sort.Slice(classification, func(i, j int) bool {
+		if classification[i].Position != classification[j].Position {
+			return classification[i].Position < classification[j].Position
+		}
+		return classification[i].DriverNumber < classification[j].DriverNumber
+	})
+
+	return classification
+}
+
+// StateAtLap returns the classification at the moment the leader completed the given lap.
+// The moment is taken as the earliest time any driver finished that lap.
+func (t *SessionTimeline) StateAtLap(lap int) (Classification, error) {
+	at, ok := t.LapEnd(lap)
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrNoLapData, lap)
+	}
+
+	return t.StateAt(at), nil
+}
+
+// LapEnd returns the earliest time any driver completed the given lap
+func (t *SessionTimeline) LapEnd(lap int) (time.Time, bool) {
+	var end time.Time
+
+	for _, laps := range t.laps {
+		// Find the requested lap for this driver
+		i := sort.Search(len(laps), func(i int) bool { return laps[i].LapNumber >= lap })
+		if i == len(laps) || laps[i].LapNumber != lap {
+			continue
+		}
+
+		// Prefer the start of the next lap and fall back to the lap duration
+		var finished time.Time
+		switch {
+		case i+1 < len(laps) && laps[i+1].LapNumber == lap+1 && !laps[i+1].DateStart.IsZero():
+			finished = laps[i+1].DateStart
+		case !laps[i].DateStart.IsZero() && laps[i].LapDuration > 0:
+			finished = laps[i].DateStart.Add(time.Duration(laps[i].LapDuration * float64(time.Second)))
+		default:
+			continue
+		}
+
+		if end.IsZero() || finished.Before(end) {
+			end = finished
+		}
+	}
+
+	return end, !end.IsZero()
+}
+
+// lapAt returns the lap a driver was on at the given time, the last lap started at or before it
+func (t *SessionTimeline) lapAt(driverNumber int, at time.Time) int {
+	starts := t.lapStarts[driverNumber]
+
+	i := sort.Search(len(starts), func(i int) bool { return starts[i].date.After(at) })
+	if i == 0 {
+		return 0
+	}
+	return starts[i-1].lapNumber
+}
@@ -0,0 +1,110 @@
+package openf1go
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionTimelineLapAt(t *testing.T) {
+	start := time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	// Lap 1 and lap 4, restarted after a red flag, have no start time
+	laps := LapsResponse{
+		{DriverNumber: 1, LapNumber: 1},
+		{DriverNumber: 1, LapNumber: 2, DateStart: at(100), LapDuration: 95},
+		{DriverNumber: 1, LapNumber: 3, DateStart: at(195)},
+		{DriverNumber: 1, LapNumber: 4},
+		{DriverNumber: 1, LapNumber: 5, DateStart: at(2000), LapDuration: 94},
+		{DriverNumber: 1, LapNumber: 6},
+		{DriverNumber: 1, LapNumber: 7, DateStart: at(2200)},
+	}
+	timeline := NewSessionTimeline(nil, nil, laps)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		lap  int
+	}{
+		{name: "before lap 2", at: at(50), lap: 1},
+		{name: "start of lap 2", at: at(100), lap: 2},
+		{name: "during lap 2", at: at(150), lap: 2},
+		{name: "during lap 3 before the unknown lap 4", at: at(1000), lap: 3},
+		{name: "during lap 5", at: at(2050), lap: 5},
+		{name: "lap 6 starts when lap 5 ended", at: at(2100), lap: 6},
+		{name: "just before lap 6", at: at(2094).Add(-time.Nanosecond), lap: 5},
+		{name: "during lap 7", at: at(2300), lap: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timeline.lapAt(1, tt.at); got != tt.lap {
+				t.Errorf("lapAt() = %d, want %d", got, tt.lap)
+			}
+		})
+	}
+
+	if got := timeline.lapAt(44, at(150)); got != 0 {
+		t.Errorf("lapAt() for a driver without laps = %d, want 0", got)
+	}
+
+	// A first lap with a start time only counts from that time
+	timeline = NewSessionTimeline(nil, nil, LapsResponse{{DriverNumber: 16, LapNumber: 1, DateStart: at(10)}, {DriverNumber: 16, LapNumber: 2, DateStart: at(110)}})
+	for _, tt := range []struct {
+		at  time.Time
+		lap int
+	}{
+		{at: at(0), lap: 0},
+		{at: at(10), lap: 1},
+		{at: at(109), lap: 1},
+		{at: at(110), lap: 2},
+		{at: at(5000), lap: 2},
+	} {
+		if got := timeline.lapAt(16, tt.at); got != tt.lap {
+			t.Errorf("lapAt() at %v = %d, want %d", tt.at.Sub(start), got, tt.lap)
+		}
+	}
+}
+
+func TestSessionTimelineStateAtLap(t *testing.T) {
+	start := time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	positions := PostionsResponse{
+		{DriverNumber: 1, Position: 1, Date: at(0)},
+		{DriverNumber: 16, Position: 2, Date: at(0)},
+		{DriverNumber: 16, Position: 1, Date: at(150)},
+		{DriverNumber: 1, Position: 2, Date: at(150)},
+	}
+	laps := LapsResponse{
+		{DriverNumber: 1, LapNumber: 1, DateStart: at(0), LapDuration: 100},
+		{DriverNumber: 1, LapNumber: 2, DateStart: at(100), LapDuration: 100},
+		{DriverNumber: 16, LapNumber: 1, DateStart: at(0), LapDuration: 101},
+		{DriverNumber: 16, LapNumber: 2, DateStart: at(101), LapDuration: 95},
+	}
+	timeline := NewSessionTimeline(positions, nil, laps)
+
+	tests := []struct {
+		lap     int
+		leader  int
+		leadLap int
+	}{
+		{lap: 1, leader: 1, leadLap: 2},
+		{lap: 2, leader: 16, leadLap: 2},
+	}
+
+	for _, tt := range tests {
+		classification, err := timeline.StateAtLap(tt.lap)
+		if err != nil {
+			t.Fatalf("StateAtLap(%d) returned %v", tt.lap, err)
+		}
+		if len(classification) != 2 || classification[0].DriverNumber != tt.leader || classification[0].LapNumber != tt.leadLap {
+			t.Errorf("StateAtLap(%d) = %+v, want driver %d leading on lap %d", tt.lap, classification, tt.leader, tt.leadLap)
+		}
+	}
+
+	if _, err := timeline.StateAtLap(10); !errors.Is(err, ErrNoLapData) {
+		t.Errorf("StateAtLap(10) returned %v, want ErrNoLapData", err)
+	}
+}
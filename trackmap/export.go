@@ -0,0 +1,115 @@
+package trackmap
+
+// Exports track maps to SVG and GeoJSON.
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// SVG writes the map as an SVG image of the given size in pixels, with a margin around the outline
+func (m TrackMap) SVG(w io.Writer, width, height int) error {
+	margin := 0.05 * float64(min(width, height))
+	scale := min(float64(width), float64(height)) - 2*margin
+
+	// project converts a normalised point into image coordinates
+	project := func(p Point) (float64, float64) {
+		return margin + p.X*scale, margin + p.Y*scale
+	}
+
+	// path formats points as an SVG path
+	path := func(points []Point, closed bool) string {
+		var b strings.Builder
+		for i, p := range points {
+			x, y := project(p)
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			fmt.Fprintf(&b, "%s%.1f %.1f ", cmd, x, y)
+		}
+		if closed {
+			b.WriteString("Z")
+		}
+		return strings.TrimSpace(b.String())
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `  <path d="%s" fill="none" stroke="#999999" stroke-width="6" stroke-linejoin="round"/>`+"\n", path(m.Outline, true))
+	for _, zone := range m.DRSZones {
+		fmt.Fprintf(&b, `  <path class="drs" d="%s" fill="none" stroke="#00c853" stroke-width="6" stroke-linejoin="round"/>`+"\n", path(zone, false))
+	}
+	// Kinds and labels are escaped as they may hold any text
+	for _, marker := range m.Markers {
+		x, y := project(marker.Point)
+		fmt.Fprintf(&b, `  <circle class="%s" cx="%.1f" cy="%.1f" r="6" fill="#e10600"/>`+"\n", html.EscapeString(string(marker.Kind)), x, y)
+		fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" font-family="sans-serif" font-size="14">%s</text>`+"\n", x+10, y-10, html.EscapeString(marker.Label))
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// geoJSON types used to encode the map
+type (
+	featureCollection struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}
+	feature struct {
+		Type       string         `json:"type"`
+		Geometry   geometry       `json:"geometry"`
+		Properties map[string]any `json:"properties"`
+	}
+	geometry struct {
+		Type        string `json:"type"`
+		Coordinates any    `json:"coordinates"`
+	}
+)
+
+// coordinates converts points into GeoJSON positions, flipping Y so north is up
+func coordinates(points []Point) [][2]float64 {
+	positions := make([][2]float64, len(points))
+	for i, p := range points {
+		positions[i] = [2]float64{p.X, 1 - p.Y}
+	}
+	return positions
+}
+
+// GeoJSON writes the map as a GeoJSON feature collection.
+// Coordinates are the normalised map coordinates rather than longitude and latitude.
+func (m TrackMap) GeoJSON(w io.Writer) error {
+	outline := coordinates(m.Outline)
+	if len(outline) > 0 {
+		outline = append(outline, outline[0])
+	}
+
+	collection := featureCollection{Type: "FeatureCollection", Features: []feature{{
+		Type:       "Feature",
+		Geometry:   geometry{Type: "LineString", Coordinates: outline},
+		Properties: map[string]any{"kind": "outline", "circuit_key": m.CircuitKey, "rotation": m.Rotation},
+	}}}
+
+	for _, zone := range m.DRSZones {
+		collection.Features = append(collection.Features, feature{
+			Type:       "Feature",
+			Geometry:   geometry{Type: "LineString", Coordinates: coordinates(zone)},
+			Properties: map[string]any{"kind": "drs"},
+		})
+	}
+
+	for _, marker := range m.Markers {
+		collection.Features = append(collection.Features, feature{
+			Type:       "Feature",
+			Geometry:   geometry{Type: "Point", Coordinates: coordinates([]Point{marker.Point})[0]},
+			Properties: map[string]any{"kind": string(marker.Kind), "label": marker.Label},
+		})
+	}
+
+	return json.NewEncoder(w).Encode(collection)
+}
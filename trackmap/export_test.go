@@ -0,0 +1,124 @@
+package trackmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testMap returns a small map with a marker label that needs escaping
+func testMap() TrackMap {
+	return TrackMap{
+		CircuitKey: 61,
+		Outline:    []Point{{0, 0}, {1, 0}, {1, 0.5}, {0, 0.5}},
+		DRSZones:   [][]Point{{{0, 0}, {1, 0}}},
+		Markers: []Marker{
+			{Kind: MarkerFinish, Label: "Start/Finish", Point: Point{0, 0}},
+			{Kind: `sector" onload="x`, Label: `<S1> & "T1"`, Point: Point{1, 0.5}},
+		},
+	}
+}
+
+// svgImage holds the parts of an exported SVG checked by the tests
+type svgImage struct {
+	Width  int `xml:"width,attr"`
+	Height int `xml:"height,attr"`
+	Paths  []struct {
+		Class string `xml:"class,attr"`
+		D     string `xml:"d,attr"`
+	} `xml:"path"`
+	Circles []struct {
+		Class string  `xml:"class,attr"`
+		X     float64 `xml:"cx,attr"`
+		Y     float64 `xml:"cy,attr"`
+	} `xml:"circle"`
+	Texts []string `xml:"text"`
+}
+
+func TestSVG(t *testing.T) {
+	var b bytes.Buffer
+	if err := testMap().SVG(&b, 200, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	var img svgImage
+	if err := xml.Unmarshal(b.Bytes(), &img); err != nil {
+		t.Fatalf("SVG() wrote invalid XML: %v\n%s", err, b.String())
+	}
+	if img.Width != 200 || img.Height != 100 {
+		t.Errorf("SVG() image is %d by %d, want 200 by 100", img.Width, img.Height)
+	}
+
+	// Margins of 5 pixels leave 90 pixels for the map
+	if len(img.Paths) != 2 || img.Paths[0].D != "M5.0 5.0 L95.0 5.0 L95.0 50.0 L5.0 50.0 Z" || img.Paths[1].Class != "drs" || img.Paths[1].D != "M5.0 5.0 L95.0 5.0" {
+		t.Errorf("SVG() wrote paths %+v", img.Paths)
+	}
+	for _, p := range img.Paths {
+		for _, field := range strings.Fields(p.D) {
+			v, err := strconv.ParseFloat(strings.TrimLeft(field, "MLZ"), 64)
+			if err == nil && (v < 5 || v > 95) {
+				t.Errorf("path %q leaves the image bounds", p.D)
+			}
+		}
+	}
+
+	if len(img.Circles) != 2 || img.Circles[1].X != 95 || img.Circles[1].Y != 50 {
+		t.Fatalf("SVG() wrote markers %+v", img.Circles)
+	}
+
+	// Kinds and labels come back unchanged once the XML is decoded
+	if img.Circles[1].Class != `sector" onload="x` {
+		t.Errorf("marker class = %q", img.Circles[1].Class)
+	}
+	if len(img.Texts) != 2 || img.Texts[0] != "Start/Finish" || img.Texts[1] != `<S1> & "T1"` {
+		t.Errorf("SVG() wrote labels %q", img.Texts)
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := testMap().GeoJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &collection); err != nil {
+		t.Fatal(err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 4 {
+		t.Fatalf("GeoJSON() = %s", b.String())
+	}
+
+	tests := []struct {
+		kind        string
+		geometry    string
+		coordinates string
+	}{
+		{kind: "outline", geometry: "LineString", coordinates: "[[0,1],[1,1],[1,0.5],[0,0.5],[0,1]]"},
+		{kind: "drs", geometry: "LineString", coordinates: "[[0,1],[1,1]]"},
+		{kind: "finish", geometry: "Point", coordinates: "[0,1]"},
+		{kind: `sector" onload="x`, geometry: "Point", coordinates: "[1,0.5]"},
+	}
+
+	for i, tt := range tests {
+		f := collection.Features[i]
+		if f.Properties["kind"] != tt.kind || f.Geometry.Type != tt.geometry || string(f.Geometry.Coordinates) != tt.coordinates {
+			t.Errorf("feature %d = %s %s %v, want %s %s %s", i, f.Geometry.Type, f.Geometry.Coordinates, f.Properties, tt.geometry, tt.coordinates, tt.kind)
+		}
+	}
+	if collection.Features[0].Properties["circuit_key"] != 61.0 || collection.Features[3].Properties["label"] != `<S1> & "T1"` {
+		t.Errorf("GeoJSON() properties = %v and %v", collection.Features[0].Properties, collection.Features[3].Properties)
+	}
+}
@@ -0,0 +1,231 @@
+package trackmap
+
+// Derives a circuit outline from the location data of a clean lap and exports it to SVG and GeoJSON.
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+)
+
+var ErrNotEnoughPoints = errors.New("not enough location samples within the lap to draw a track map")
+
+// Point represents a normalised position on the map, with both coordinates within [0, 1]
+type Point struct {
+	X float64 // Horizontal position, 0 is the left edge
+	Y float64 // Vertical position, 0 is the top edge
+}
+
+// MarkerKind identifies the type of a marker on the map
+type MarkerKind string
+
+const (
+	MarkerFinish MarkerKind = "finish" // Start/finish line
+	MarkerSector MarkerKind = "sector" // End of sector 1 or sector 2
+)
+
+// Marker represents a labelled point on the map
+type Marker struct {
+	Kind  MarkerKind // Type of the marker
+	Label string     // Label of the marker, e.g. "S1"
+	Point Point      // Position of the marker
+}
+
+// TrackMap represents a normalised circuit outline with its markers
+type TrackMap struct {
+	CircuitKey int       // Unique identifier for the circuit
+	Rotation   float64   // Rotation applied to the raw coordinates in degrees
+	Outline    []Point   // Smoothed outline of the circuit in driving order
+	Markers    []Marker  // Start/finish and sector markers
+	DRSZones   [][]Point // Sections of the outline where DRS was open
+}
+
+// Options configures how a track map is derived
+type Options struct {
+	Smoothing  int     // Number of samples on either side averaged to smooth the outline
+	Rotation   float64 // Rotation in degrees applied to the raw coordinates
+	AutoRotate bool    // Rotate so the longest axis of the circuit is horizontal, ignoring Rotation
+}
+
+// DefaultOptions returns the options used when none are provided
+func DefaultOptions() Options {
+	return Options{Smoothing: 2, AutoRotate: true}
+}
+
+// Fetch fetches the location and car data of a clean lap and builds the map of the session's circuit
+func Fetch(c *openf1go.Client, session openf1go.Session, lap openf1go.Lap, opts Options) (TrackMap, error) {
+	if lap.DateStart.IsZero() || lap.LapDuration <= 0 {
+		return TrackMap{}, ErrNotEnoughPoints
+	}
+	end := lap.DateStart.Add(seconds(lap.LapDuration))
+
+	locations, err := c.GetLocationsBetween(openf1go.Location{SessionKey: lap.SessionKey, DriverNumber: lap.DriverNumber}, lap.DateStart, end)
+	if err != nil {
+		return TrackMap{}, err
+	}
+
+	carData, err := c.GetCarDataBetween(openf1go.CarData{SessionKey: lap.SessionKey, DriverNumber: lap.DriverNumber}, lap.DateStart, end)
+	if err != nil {
+		return TrackMap{}, err
+	}
+
+	return Build(session.CircuitKey, lap, locations, carData, opts)
+}
+
+// Build derives the track map from the location data of a clean lap.
+// Car data is optional and used to mark the DRS zones.
+func Build(circuitKey int, lap openf1go.Lap, locations openf1go.LocationResponse, carData openf1go.CarDataResponse, opts Options) (TrackMap, error) {
+	// Keep only the samples of the lap in time order
+	end := lap.DateStart.Add(seconds(lap.LapDuration))
+	samples := openf1go.LocationResponse{}
+	for _, l := range locations {
+		if l.DriverNumber == lap.DriverNumber && !l.Date.Before(lap.DateStart) && l.Date.Before(end) {
+			samples = append(samples, l)
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Date.Before(samples[j].Date) })
+	if len(samples) < 3 {
+		return TrackMap{}, ErrNotEnoughPoints
+	}
+
+	raw := make([]Point, len(samples))
+	for i, s := range samples {
+		raw[i] = Point{X: float64(s.X), Y: float64(s.Y)}
+	}
+
+	m := TrackMap{CircuitKey: circuitKey, Rotation: opts.Rotation}
+	if opts.AutoRotate {
+		m.Rotation = principalAngle(raw)
+	}
+	m.Outline = normalise(rotate(smooth(raw, opts.Smoothing), m.Rotation))
+
+	// indexAt returns the outline index of the sample closest in time
+	indexAt := func(t time.Time) int {
+		i := sort.Search(len(samples), func(i int) bool { return !samples[i].Date.Before(t) })
+		if i == len(samples) {
+			return len(samples) - 1
+		}
+		if i > 0 && t.Sub(samples[i-1].Date) < samples[i].Date.Sub(t) {
+			return i - 1
+		}
+		return i
+	}
+
+	// Sector markers are placed at the time each sector ended
+	m.Markers = []Marker{{Kind: MarkerFinish, Label: "Start/Finish", Point: m.Outline[0]}}
+	if lap.DurationSector1 > 0 {
+		m.Markers = append(m.Markers, Marker{Kind: MarkerSector, Label: "S1", Point: m.Outline[indexAt(lap.DateStart.Add(seconds(lap.DurationSector1)))]})
+		if lap.DurationSector2 > 0 {
+			m.Markers = append(m.Markers, Marker{Kind: MarkerSector, Label: "S2", Point: m.Outline[indexAt(lap.DateStart.Add(seconds(lap.DurationSector1+lap.DurationSector2)))]})
+		}
+	}
+
+	// DRS zones are the sections where the car reported DRS as open
+	m.DRSZones = [][]Point{}
+	sorted := append(openf1go.CarDataResponse{}, carData...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+	var zoneStart *time.Time
+	for i, d := range sorted {
+		open := d.DriverNumber == lap.DriverNumber && d.Drs >= 10 && !d.Date.Before(lap.DateStart) && d.Date.Before(end)
+		if open && zoneStart == nil {
+			zoneStart = &sorted[i].Date
+		}
+		if zoneStart != nil && (!open || i == len(sorted)-1) {
+			from, to := indexAt(*zoneStart), indexAt(d.Date)
+			if to > from {
+				m.DRSZones = append(m.DRSZones, append([]Point{}, m.Outline[from:to+1]...))
+			}
+			zoneStart = nil
+		}
+	}
+
+	return m, nil
+}
+
+// seconds converts a number of seconds into a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// smooth averages each point with its neighbours, wrapping around as the lap is a closed loop
+func smooth(points []Point, window int) []Point {
+	if window <= 0 {
+		return points
+	}
+
+	n := len(points)
+	smoothed := make([]Point, n)
+	for i := range points {
+		var sum Point
+		for k := -window; k <= window; k++ {
+			p := points[((i+k)%n+n)%n]
+			sum.X += p.X
+			sum.Y += p.Y
+		}
+		count := float64(2*window + 1)
+		smoothed[i] = Point{X: sum.X / count, Y: sum.Y / count}
+	}
+
+	return smoothed
+}
+
+// principalAngle returns the rotation in degrees that aligns the longest axis of the points horizontally
+func principalAngle(points []Point) float64 {
+	var meanX, meanY float64
+	for _, p := range points {
+		meanX += p.X
+		meanY += p.Y
+	}
+	meanX /= float64(len(points))
+	meanY /= float64(len(points))
+
+	var sxx, syy, sxy float64
+	for _, p := range points {
+		dx, dy := p.X-meanX, p.Y-meanY
+		sxx += dx * dx
+		syy += dy * dy
+		sxy += dx * dy
+	}
+
+	// Angle of the principal axis of the covariance matrix
+	axis := 0.5 * math.Atan2(2*sxy, sxx-syy)
+	return -axis * 180 / math.Pi
+}
+
+// rotate rotates the points around the origin by the given angle in degrees
+func rotate(points []Point, degrees float64) []Point {
+	theta := degrees * math.Pi / 180
+	sin, cos := math.Sin(theta), math.Cos(theta)
+
+	rotated := make([]Point, len(points))
+	for i, p := range points {
+		rotated[i] = Point{X: p.X*cos - p.Y*sin, Y: p.X*sin + p.Y*cos}
+	}
+
+	return rotated
+}
+
+// normalise scales the points into [0, 1] keeping the aspect ratio, with Y growing downwards
+func normalise(points []Point) []Point {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	scale := math.Max(maxX-minX, maxY-minY)
+	if scale == 0 {
+		scale = 1
+	}
+
+	normalised := make([]Point, len(points))
+	for i, p := range points {
+		normalised[i] = Point{X: (p.X - minX) / scale, Y: (maxY - p.Y) / scale}
+	}
+
+	return normalised
+}
@@ -0,0 +1,213 @@
+package trackmap
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	openf1go "github.com/stephenhoran/open-f1-go"
+	"github.com/stephenhoran/open-f1-go/openf1test"
+)
+
+var lapStart = time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+
+// after returns the time the given number of seconds after lapStart
+func after(s int) time.Time {
+	return lapStart.Add(time.Duration(s) * time.Second)
+}
+
+// rectangleLap returns a 24 second lap of driver 1 around a 2000 by 1000 meter rectangle, one location a second.
+// The car starts in the corner at the origin and reaches the other corners after 8, 12 and 20 seconds.
+func rectangleLap() (openf1go.Lap, openf1go.LocationResponse) {
+	lap := openf1go.Lap{DriverNumber: 1, LapNumber: 2, DateStart: lapStart, LapDuration: 24, DurationSector1: 8, DurationSector2: 4, DurationSector3: 12}
+
+	locations := openf1go.LocationResponse{}
+	add := func(x, y int) {
+		locations = append(locations, openf1go.Location{DriverNumber: 1, Date: after(len(locations)), X: x, Y: y})
+	}
+	for x := 0; x < 2000; x += 250 {
+		add(x, 0)
+	}
+	for y := 0; y < 1000; y += 250 {
+		add(2000, y)
+	}
+	for x := 2000; x > 0; x -= 250 {
+		add(x, 1000)
+	}
+	for y := 1000; y > 0; y -= 250 {
+		add(0, y)
+	}
+
+	return lap, locations
+}
+
+// near reports whether two points are within rounding error of each other
+func near(a, b Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestBuild(t *testing.T) {
+	lap, locations := rectangleLap()
+	locations = append(locations,
+		openf1go.Location{DriverNumber: 44, Date: after(5), X: 9000, Y: 9000},  // Another driver
+		openf1go.Location{DriverNumber: 1, Date: after(24), X: -9000, Y: 9000}, // After the lap
+	)
+	carData := openf1go.CarDataResponse{
+		{DriverNumber: 1, Date: after(0), Drs: 8},
+		{DriverNumber: 1, Date: after(1), Drs: 12},
+		{DriverNumber: 1, Date: after(2), Drs: 12},
+		{DriverNumber: 1, Date: after(3), Drs: 14},
+		{DriverNumber: 1, Date: after(4), Drs: 8},
+		{DriverNumber: 44, Date: after(10), Drs: 12},
+	}
+
+	m, err := Build(10, lap, locations, carData, Options{})
+	if err != nil {
+		t.Fatalf("Build() returned %v", err)
+	}
+	if m.CircuitKey != 10 || m.Rotation != 0 || len(m.Outline) != 24 {
+		t.Fatalf("Build() = circuit %d rotated %v with %d points, want circuit 10 unrotated with 24 points", m.CircuitKey, m.Rotation, len(m.Outline))
+	}
+
+	// The outline keeps the aspect ratio with Y growing downwards
+	for i, want := range map[int]Point{0: {0, 0.5}, 8: {1, 0.5}, 12: {1, 0}, 20: {0, 0}} {
+		if !near(m.Outline[i], want) {
+			t.Errorf("outline point %d = %+v, want %+v", i, m.Outline[i], want)
+		}
+	}
+
+	markers := []Marker{
+		{Kind: MarkerFinish, Label: "Start/Finish", Point: Point{0, 0.5}},
+		{Kind: MarkerSector, Label: "S1", Point: Point{1, 0.5}},
+		{Kind: MarkerSector, Label: "S2", Point: Point{1, 0}},
+	}
+	if len(m.Markers) != len(markers) {
+		t.Fatalf("Build() returned markers %+v, want %+v", m.Markers, markers)
+	}
+	for i, want := range markers {
+		if got := m.Markers[i]; got.Kind != want.Kind || got.Label != want.Label || !near(got.Point, want.Point) {
+			t.Errorf("marker %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if len(m.DRSZones) != 1 || len(m.DRSZones[0]) != 4 || !near(m.DRSZones[0][0], m.Outline[1]) || !near(m.DRSZones[0][3], m.Outline[4]) {
+		t.Errorf("Build() returned DRS zones %+v, want outline points 1 to 4", m.DRSZones)
+	}
+}
+
+func TestBuildOptions(t *testing.T) {
+	lap, locations := rectangleLap()
+
+	// Swapping the axes makes the circuit taller than wide
+	for i, l := range locations {
+		locations[i].X, locations[i].Y = l.Y, l.X
+	}
+
+	tests := []struct {
+		name          string
+		opts          Options
+		width, height float64 // Size of the outline
+	}{
+		{name: "as recorded", opts: Options{}, width: 0.5, height: 1},
+		{name: "rotated", opts: Options{Rotation: 90}, width: 1, height: 0.5},
+		{name: "auto rotated", opts: Options{Rotation: 45, AutoRotate: true}, width: 1, height: 0.5},
+		{name: "smoothed", opts: DefaultOptions(), width: 1, height: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Build(10, lap, locations, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Build() returned %v", err)
+			}
+
+			var width, height float64
+			for _, p := range m.Outline {
+				if p.X < 0 || p.X > 1 || p.Y < 0 || p.Y > 1 {
+					t.Fatalf("outline point %+v is outside the map", p)
+				}
+				width, height = math.Max(width, p.X), math.Max(height, p.Y)
+			}
+			if math.Abs(width-tt.width) > 1e-9 || math.Abs(height-tt.height) > 1e-9 {
+				t.Errorf("outline is %v by %v, want %v by %v", width, height, tt.width, tt.height)
+			}
+			if len(m.DRSZones) != 0 {
+				t.Errorf("Build() without car data returned DRS zones %+v", m.DRSZones)
+			}
+		})
+	}
+}
+
+func TestBuildNotEnoughPoints(t *testing.T) {
+	lap, locations := rectangleLap()
+
+	tests := []struct {
+		name      string
+		lap       openf1go.Lap
+		locations openf1go.LocationResponse
+	}{
+		{name: "two samples", lap: lap, locations: locations[:2]},
+		{name: "another driver", lap: openf1go.Lap{DriverNumber: 44, DateStart: lapStart, LapDuration: 24}, locations: locations},
+		{name: "lap without a duration", lap: openf1go.Lap{DriverNumber: 1, DateStart: lapStart}, locations: locations},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Build(10, tt.lap, tt.locations, nil, DefaultOptions()); !errors.Is(err, ErrNotEnoughPoints) {
+				t.Errorf("Build() returned %v, want ErrNotEnoughPoints", err)
+			}
+		})
+	}
+}
+
+func TestProjection(t *testing.T) {
+	// A diagonal line is rotated onto the horizontal axis
+	diagonal := []Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}
+	if got := principalAngle(diagonal); math.Abs(got+45) > 1e-9 {
+		t.Errorf("principalAngle() = %v, want -45", got)
+	}
+	for _, p := range rotate(diagonal, principalAngle(diagonal)) {
+		if math.Abs(p.Y) > 1e-9 {
+			t.Errorf("rotated point %+v is off the horizontal axis", p)
+		}
+	}
+
+	if got := rotate([]Point{{1, 0}}, 90)[0]; !near(got, Point{0, 1}) {
+		t.Errorf("rotate() by 90 degrees = %+v, want {0 1}", got)
+	}
+
+	// Smoothing wraps around the closed lap
+	smoothed := smooth([]Point{{0, 0}, {3, 0}, {0, 3}}, 1)
+	for _, p := range smoothed {
+		if !near(p, Point{1, 1}) {
+			t.Errorf("smooth() = %+v, want every point at {1 1}", smoothed)
+			break
+		}
+	}
+
+	// A single point has no extent to scale
+	if got := normalise([]Point{{5, 5}, {5, 5}}); !near(got[0], Point{0, 0}) {
+		t.Errorf("normalise() of a single point = %+v, want {0 0}", got)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := openf1test.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	// The fixtures hold the locations of the first seconds of lap 3 of driver 1
+	lap := openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 3, DateStart: time.Date(2023, 9, 17, 12, 3, 36, 304000000, time.UTC), LapDuration: 98.424}
+	m, err := Fetch(client, openf1go.Session{SessionKey: 9158, CircuitKey: 61}, lap, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Fetch() returned %v", err)
+	}
+	if m.CircuitKey != 61 || len(m.Outline) != 40 {
+		t.Errorf("Fetch() = circuit %d with %d points, want circuit 61 with 40 points", m.CircuitKey, len(m.Outline))
+	}
+
+	if _, err := Fetch(client, openf1go.Session{SessionKey: 9158}, openf1go.Lap{SessionKey: 9158, DriverNumber: 1, LapNumber: 1}, DefaultOptions()); !errors.Is(err, ErrNotEnoughPoints) {
+		t.Errorf("Fetch() of a lap without a start returned %v, want ErrNotEnoughPoints", err)
+	}
+}
@@ -0,0 +1,192 @@
+package openf1go
+
+// Extracts safety car, virtual safety car and red flag periods from race control messages.
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrackStatus represents the state of the track
+type TrackStatus string
+
+const (
+	TrackStatusGreen            TrackStatus = "GREEN"     // Racing under green flag conditions
+	TrackStatusSafetyCar        TrackStatus = "SC"        // Safety car deployed
+	TrackStatusVirtualSafetyCar TrackStatus = "VSC"       // Virtual safety car deployed
+	TrackStatusRedFlag          TrackStatus = "RED"       // Session stopped under red flag
+	TrackStatusChequered        TrackStatus = "CHEQUERED" // Chequered flag shown
+)
+
+// NeutralisationPeriod represents a period during which racing was neutralised
+type NeutralisationPeriod struct {
+	Type         TrackStatus // Safety car, virtual safety car or red flag
+	Start        time.Time   // Time the period started
+	End          time.Time   // Time the period ended, zero when still ongoing
+	StartLap     int         // Lap on which the period started
+	EndLap       int         // Lap on which the period ended, zero when still ongoing
+	StartMessage string      // Race control message that started the period
+	EndMessage   string      // Race control message that ended the period
+}
+
+// Contains reports whether the given time falls within the period
+func (p NeutralisationPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && (p.End.IsZero() || t.Before(p.End))
+}
+
+// ContainsLap reports whether the given lap was at least partly run within the period
+func (p NeutralisationPeriod) ContainsLap(lap int) bool {
+	return lap >= p.StartLap && (p.EndLap == 0 || lap <= p.EndLap)
+}
+
+// TrackStatusChange represents a change of track status
+type TrackStatusChange struct {
+	Date      time.Time   // Time of the change
+	LapNumber int         // Lap on which the change occurred
+	Status    TrackStatus // New status of the track
+	Message   string      // Race control message that caused the change
+}
+
+// raceControlSignal classifies a race control message into the track status change it signals
+func raceControlSignal(rc RaceControl) (status TrackStatus, ending bool, ok bool) {
+	message := strings.ToUpper(rc.Message)
+	flag := strings.ToUpper(rc.Flag)
+
+	switch {
+	case strings.Contains(message, "VIRTUAL SAFETY CAR DEPLOYED"):
+		return TrackStatusVirtualSafetyCar, false, true
+	case strings.Contains(message, "VIRTUAL SAFETY CAR ENDING"):
+		return TrackStatusVirtualSafetyCar, true, true
+	case strings.Contains(message, "SAFETY CAR DEPLOYED"):
+		return TrackStatusSafetyCar, false, true
+	case strings.Contains(message, "SAFETY CAR IN THIS LAP"):
+		return TrackStatusSafetyCar, true, true
+	case flag == "CHEQUERED":
+		// Checked before red flags as "CHEQUERED FLAG" contains "RED FLAG"
+		return TrackStatusChequered, false, true
+	case flag == "RED" || strings.Contains(message, "RED FLAG"):
+		return TrackStatusRedFlag, false, true
+	case strings.EqualFold(rc.Scope, "Track") && (flag == "GREEN" || flag == "CLEAR"):
+		return TrackStatusGreen, false, true
+	}
+
+	return "", false, false
+}
+
+// NeutralisationPeriods returns the safety car, virtual safety car and red flag periods ordered by start time.
+// A safety car period ends at the track clear or green flag following "SAFETY CAR IN THIS LAP",
+// a virtual safety car period ends at "VIRTUAL SAFETY CAR ENDING" and a red flag period ends at the next green flag.
+func (r RaceControlResponse) NeutralisationPeriods() []NeutralisationPeriod {
+	messages := append(RaceControlResponse{}, r...)
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].Date.Before(messages[j].Date) })
+
+	periods := []NeutralisationPeriod{}
+	var open *NeutralisationPeriod
+	safetyCarEnding := false
+
+	// closePeriod ends the open period with the given message
+	closePeriod := func(rc RaceControl) {
+		if open == nil {
+			return
+		}
+		open.End = rc.Date
+		open.EndLap = rc.LapNumber
+		open.EndMessage = rc.Message
+		if open.EndLap == 0 {
+			open.EndLap = open.StartLap
+		}
+		periods = append(periods, *open)
+		open = nil
+		safetyCarEnding = false
+	}
+
+	for _, rc := range messages {
+		status, ending, ok := raceControlSignal(rc)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case status == TrackStatusGreen || status == TrackStatusChequered:
+			// A red flag always ends at a green flag, a safety car only once it has been called in
+			if open != nil && (open.Type == TrackStatusRedFlag || safetyCarEnding || status == TrackStatusChequered) {
+				closePeriod(rc)
+			}
+		case status == TrackStatusSafetyCar && ending:
+			safetyCarEnding = open != nil && open.Type == TrackStatusSafetyCar
+		case status == TrackStatusVirtualSafetyCar && ending:
+			if open != nil && open.Type == TrackStatusVirtualSafetyCar {
+				closePeriod(rc)
+			}
+		default:
+			// A new neutralisation replaces the open one, e.g. a red flag shown behind the safety car
+			if open != nil && open.Type == status {
+				continue
+			}
+			closePeriod(rc)
+			open = &NeutralisationPeriod{Type: status, Start: rc.Date, StartLap: rc.LapNumber, StartMessage: rc.Message}
+		}
+	}
+
+	// Keep a period that has not ended yet
+	if open != nil {
+		periods = append(periods, *open)
+	}
+
+	return periods
+}
+
+// TrackStatusTimeline returns every change of track status ordered by time, starting under green flag conditions
+func (r RaceControlResponse) TrackStatusTimeline() []TrackStatusChange {
+	changes := []TrackStatusChange{}
+
+	// Find the first chequered flag, which ends the timeline
+	var chequered *RaceControl
+	for i, rc := range r {
+		if strings.EqualFold(rc.Flag, "CHEQUERED") && (chequered == nil || rc.Date.Before(chequered.Date)) {
+			chequered = &r[i]
+		}
+	}
+
+	periods := r.NeutralisationPeriods()
+	for i, period := range periods {
+		changes = append(changes, TrackStatusChange{Date: period.Start, LapNumber: period.StartLap, Status: period.Type, Message: period.StartMessage})
+
+		// A period ended by the chequered flag is followed by the chequered flag rather than green,
+		// and one replaced by another neutralisation, e.g. a red flag shown behind the safety car, by that neutralisation
+		if period.End.IsZero() || (chequered != nil && !period.End.Before(chequered.Date)) {
+			continue
+		}
+		if i+1 < len(periods) && periods[i+1].Start.Equal(period.End) {
+			continue
+		}
+		changes = append(changes, TrackStatusChange{Date: period.End, LapNumber: period.EndLap, Status: TrackStatusGreen, Message: period.EndMessage})
+	}
+
+	if chequered != nil {
+		changes = append(changes, TrackStatusChange{Date: chequered.Date, LapNumber: chequered.LapNumber, Status: TrackStatusChequered, Message: chequered.Message})
+	}
+
+	return changes
+}
+
+// NeutralisedLaps returns the set of laps run at least partly under a neutralisation
+func NeutralisedLaps(periods []NeutralisationPeriod) map[int]bool {
+	laps := map[int]bool{}
+
+	for _, period := range periods {
+		if period.StartLap == 0 {
+			continue
+		}
+		end := period.EndLap
+		if end == 0 {
+			end = period.StartLap
+		}
+		for lap := period.StartLap; lap <= end; lap++ {
+			laps[lap] = true
+		}
+	}
+
+	return laps
+}
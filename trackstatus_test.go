@@ -0,0 +1,196 @@
+package openf1go
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRaceControlSignal(t *testing.T) {
+	tests := []struct {
+		name    string
+		rc      RaceControl
+		status  TrackStatus
+		ending  bool
+		matches bool
+	}{
+		{
+			name:    "safety car deployed",
+			rc:      RaceControl{Category: "SafetyCar", Message: "SAFETY CAR DEPLOYED"},
+			status:  TrackStatusSafetyCar,
+			matches: true,
+		},
+		{
+			name:    "safety car in this lap",
+			rc:      RaceControl{Category: "SafetyCar", Message: "SAFETY CAR IN THIS LAP"},
+			status:  TrackStatusSafetyCar,
+			ending:  true,
+			matches: true,
+		},
+		{
+			name:    "virtual safety car deployed",
+			rc:      RaceControl{Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR DEPLOYED"},
+			status:  TrackStatusVirtualSafetyCar,
+			matches: true,
+		},
+		{
+			name:    "virtual safety car ending",
+			rc:      RaceControl{Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR ENDING"},
+			status:  TrackStatusVirtualSafetyCar,
+			ending:  true,
+			matches: true,
+		},
+		{
+			name:    "red flag",
+			rc:      RaceControl{Category: "Flag", Flag: "RED", Scope: "Track", Message: "RED FLAG"},
+			status:  TrackStatusRedFlag,
+			matches: true,
+		},
+		{
+			// "CHEQUERED FLAG" contains "RED FLAG"
+			name:    "chequered flag",
+			rc:      RaceControl{Category: "Flag", Flag: "CHEQUERED", Scope: "Track", Message: "CHEQUERED FLAG"},
+			status:  TrackStatusChequered,
+			matches: true,
+		},
+		{
+			name:    "track clear",
+			rc:      RaceControl{Category: "Flag", Flag: "CLEAR", Scope: "Track", Message: "TRACK CLEAR"},
+			status:  TrackStatusGreen,
+			matches: true,
+		},
+		{
+			name: "sector yellow",
+			rc:   RaceControl{Category: "Flag", Flag: "YELLOW", Scope: "Sector", Message: "YELLOW IN TRACK SECTOR 4"},
+		},
+		{
+			name: "driver blue flag",
+			rc:   RaceControl{Category: "Flag", Flag: "BLUE", Scope: "Driver", Message: "WAVED BLUE FLAG FOR CAR 2 (SAR)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ending, ok := raceControlSignal(tt.rc)
+			if status != tt.status || ending != tt.ending || ok != tt.matches {
+				t.Errorf("raceControlSignal() = %q, %v, %v, want %q, %v, %v", status, ending, ok, tt.status, tt.ending, tt.matches)
+			}
+		})
+	}
+}
+
+func TestNeutralisationPeriods(t *testing.T) {
+	start := time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	messages := RaceControlResponse{
+		{Date: at(0), LapNumber: 1, Category: "Flag", Flag: "GREEN", Scope: "Track", Message: "GREEN LIGHT - PIT EXIT OPEN"},
+		{Date: at(10), LapNumber: 5, Category: "SafetyCar", Message: "SAFETY CAR DEPLOYED"},
+		{Date: at(14), LapNumber: 7, Category: "SafetyCar", Message: "SAFETY CAR IN THIS LAP"},
+		{Date: at(16), LapNumber: 8, Category: "Flag", Flag: "CLEAR", Scope: "Track", Message: "TRACK CLEAR"},
+		{Date: at(30), LapNumber: 15, Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR DEPLOYED"},
+		{Date: at(32), LapNumber: 16, Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR ENDING"},
+		{Date: at(90), LapNumber: 62, Category: "Flag", Flag: "CHEQUERED", Scope: "Track", Message: "CHEQUERED FLAG"},
+	}
+
+	want := []NeutralisationPeriod{
+		{Type: TrackStatusSafetyCar, Start: at(10), End: at(16), StartLap: 5, EndLap: 8, StartMessage: "SAFETY CAR DEPLOYED", EndMessage: "TRACK CLEAR"},
+		{Type: TrackStatusVirtualSafetyCar, Start: at(30), End: at(32), StartLap: 15, EndLap: 16, StartMessage: "VIRTUAL SAFETY CAR DEPLOYED", EndMessage: "VIRTUAL SAFETY CAR ENDING"},
+	}
+
+	got := messages.NeutralisationPeriods()
+	if len(got) != len(want) {
+		t.Fatalf("NeutralisationPeriods() returned %d periods, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("period %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrackStatusTimeline(t *testing.T) {
+	start := time.Date(2023, 9, 17, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	green := RaceControl{Date: at(0), LapNumber: 1, Category: "Flag", Flag: "GREEN", Scope: "Track", Message: "GREEN LIGHT - PIT EXIT OPEN"}
+	chequered := RaceControl{Date: at(90), LapNumber: 62, Category: "Flag", Flag: "CHEQUERED", Scope: "Track", Message: "CHEQUERED FLAG"}
+
+	tests := []struct {
+		name     string
+		messages RaceControlResponse
+		want     []TrackStatusChange
+	}{
+		{
+			name: "safety car and virtual safety car",
+			messages: RaceControlResponse{
+				green,
+				{Date: at(10), LapNumber: 5, Category: "SafetyCar", Message: "SAFETY CAR DEPLOYED"},
+				{Date: at(14), LapNumber: 7, Category: "SafetyCar", Message: "SAFETY CAR IN THIS LAP"},
+				{Date: at(16), LapNumber: 8, Category: "Flag", Flag: "CLEAR", Scope: "Track", Message: "TRACK CLEAR"},
+				{Date: at(30), LapNumber: 15, Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR DEPLOYED"},
+				{Date: at(32), LapNumber: 16, Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR ENDING"},
+				chequered,
+			},
+			want: []TrackStatusChange{
+				{Date: at(10), LapNumber: 5, Status: TrackStatusSafetyCar, Message: "SAFETY CAR DEPLOYED"},
+				{Date: at(16), LapNumber: 8, Status: TrackStatusGreen, Message: "TRACK CLEAR"},
+				{Date: at(30), LapNumber: 15, Status: TrackStatusVirtualSafetyCar, Message: "VIRTUAL SAFETY CAR DEPLOYED"},
+				{Date: at(32), LapNumber: 16, Status: TrackStatusGreen, Message: "VIRTUAL SAFETY CAR ENDING"},
+				{Date: at(90), LapNumber: 62, Status: TrackStatusChequered, Message: "CHEQUERED FLAG"},
+			},
+		},
+		{
+			name: "red flag behind the safety car",
+			messages: RaceControlResponse{
+				green,
+				{Date: at(10), LapNumber: 5, Category: "SafetyCar", Message: "SAFETY CAR DEPLOYED"},
+				{Date: at(12), LapNumber: 6, Category: "Flag", Flag: "RED", Scope: "Track", Message: "RED FLAG"},
+				{Date: at(40), LapNumber: 6, Category: "Flag", Flag: "GREEN", Scope: "Track", Message: "GREEN LIGHT - PIT EXIT OPEN"},
+				chequered,
+			},
+			want: []TrackStatusChange{
+				{Date: at(10), LapNumber: 5, Status: TrackStatusSafetyCar, Message: "SAFETY CAR DEPLOYED"},
+				{Date: at(12), LapNumber: 6, Status: TrackStatusRedFlag, Message: "RED FLAG"},
+				{Date: at(40), LapNumber: 6, Status: TrackStatusGreen, Message: "GREEN LIGHT - PIT EXIT OPEN"},
+				{Date: at(90), LapNumber: 62, Status: TrackStatusChequered, Message: "CHEQUERED FLAG"},
+			},
+		},
+		{
+			name: "ended by the chequered flag",
+			messages: RaceControlResponse{
+				green,
+				{Date: at(85), LapNumber: 60, Category: "SafetyCar", Message: "SAFETY CAR DEPLOYED"},
+				chequered,
+			},
+			want: []TrackStatusChange{
+				{Date: at(85), LapNumber: 60, Status: TrackStatusSafetyCar, Message: "SAFETY CAR DEPLOYED"},
+				{Date: at(90), LapNumber: 62, Status: TrackStatusChequered, Message: "CHEQUERED FLAG"},
+			},
+		},
+		{
+			name: "still neutralised",
+			messages: RaceControlResponse{
+				green,
+				{Date: at(10), LapNumber: 5, Category: "SafetyCar", Message: "VIRTUAL SAFETY CAR DEPLOYED"},
+			},
+			want: []TrackStatusChange{
+				{Date: at(10), LapNumber: 5, Status: TrackStatusVirtualSafetyCar, Message: "VIRTUAL SAFETY CAR DEPLOYED"},
+			},
+		},
+		{
+			name:     "green throughout",
+			messages: RaceControlResponse{green, chequered},
+			want:     []TrackStatusChange{{Date: at(90), LapNumber: 62, Status: TrackStatusChequered, Message: "CHEQUERED FLAG"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.messages.TrackStatusTimeline()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TrackStatusTimeline() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,32 @@
 package openf1go
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 )
 
+// Comparison operators that may end an Arg key to filter on a range, e.g. Arg{Key: "date>=", Value: "2023-09-16T13:03:35"}
+var comparisonOperators = []string{">=", "<=", ">", "<"}
+
 type Arg struct {
 	Key   string
 	Value string
 }
 
+// splitKey separates a key into the field name and its comparison operator, if any
+func splitKey(key string) (string, string) {
+	for _, op := range comparisonOperators {
+		if strings.HasSuffix(key, op) {
+			return strings.TrimSuffix(key, op), op
+		}
+	}
+	return key, ""
+}
+
 func UrlBuilder(s string, args []Arg) (*url.URL, error) {
 	u, err := url.Parse(s)
 	if err != nil {
@@ -23,13 +39,62 @@ func UrlBuilder(s string, args []Arg) (*url.URL, error) {
 		q.Set(arg.Key, arg.Value)
 	}
 
-	u.RawQuery = q.Encode()
+	keys := make([]string, 0, len(q))
+	for key := range q {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Comparison operators are written unescaped as the API expects, e.g. date>=2023-09-16
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name, op := splitKey(key)
+		if op == "" {
+			op = "="
+		}
+		parts = append(parts, url.QueryEscape(name)+op+url.QueryEscape(q.Get(key)))
+	}
+
+	u.RawQuery = strings.Join(parts, "&")
 
 	return u, nil
 }
 
 func GetHTTPRequest(url *url.URL) ([]byte, error) {
-	resp, err := http.Get(url.String())
+	return getHTTPRequest(context.Background(), http.DefaultClient, url)
+}
+
+// get makes a GET request with the HTTP client of the Client, or asks its data source when set
+func (c *Client) get(url *url.URL) ([]byte, error) {
+	return c.getContext(context.Background(), url)
+}
+
+// getContext makes a GET request like get, cancelled with the context
+func (c *Client) getContext(ctx context.Context, url *url.URL) ([]byte, error) {
+	if c.source != nil {
+		return c.source.Fetch(ctx, url)
+	}
+	return getHTTPRequest(ctx, &c.client, url)
+}
+
+// Raw fetches the records of an endpoint matching the args as the JSON array returned by the API,
+// e.g. Raw(ctx, "car_data", []Arg{{Key: "session_key", Value: "9158"}, {Key: "speed>=", Value: "300"}})
+func (c *Client) Raw(ctx context.Context, endpoint string, args []Arg) ([]byte, error) {
+	url, err := UrlBuilder(c.baseUrl+"/"+endpoint, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.getContext(ctx, url)
+}
+
+// getHTTPRequest makes a GET request with the given HTTP client and returns the response body
+func getHTTPRequest(ctx context.Context, client *http.Client, url *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -40,5 +105,32 @@ func GetHTTPRequest(url *url.URL) ([]byte, error) {
 		return nil, err
 	}
 
-	return body, err
+	return body, checkStatus(resp)
+}
+
+// StatusError is returned for responses without a 2xx status, along with the response body.
+// It matches ErrRateLimited for 429 responses and ErrUnexpectedStatus for any other status.
+type StatusError struct {
+	StatusCode int    // Status code of the response
+	Status     string // Status line of the response, e.g. "404 Not Found"
+}
+
+func (e *StatusError) Error() string {
+	return e.Unwrap().Error() + ": " + e.Status
+}
+
+// Unwrap returns ErrRateLimited or ErrUnexpectedStatus depending on the status
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return ErrUnexpectedStatus
+}
+
+// checkStatus returns an error for responses that do not carry data
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
 }
@@ -0,0 +1,54 @@
+package openf1go
+
+import "testing"
+
+func TestUrlBuilder(t *testing.T) {
+	tests := []struct {
+		name string
+		args []Arg
+		want string
+	}{
+		{name: "no args", want: "https://api.openf1.org/v1/laps"},
+		{
+			name: "sorted keys",
+			args: []Arg{{Key: "session_key", Value: "9158"}, {Key: "driver_number", Value: "1"}},
+			want: "https://api.openf1.org/v1/laps?driver_number=1&session_key=9158",
+		},
+		{
+			name: "comparison operators",
+			args: []Arg{{Key: "speed>=", Value: "300"}, {Key: "date<", Value: "2023-09-16T13:03:35+00:00"}, {Key: "lap_number>", Value: "3"}, {Key: "rpm<=", Value: "11000"}},
+			want: "https://api.openf1.org/v1/laps?date<2023-09-16T13%3A03%3A35%2B00%3A00&lap_number>3&rpm<=11000&speed>=300",
+		},
+		{
+			name: "range on one field",
+			args: []Arg{{Key: "date<", Value: "2023-09-17"}, {Key: "date>=", Value: "2023-09-16"}},
+			want: "https://api.openf1.org/v1/laps?date<2023-09-17&date>=2023-09-16",
+		},
+		{
+			name: "escaped values",
+			args: []Arg{{Key: "team_name", Value: "Red Bull Racing"}, {Key: "country_code", Value: "a&b"}},
+			want: "https://api.openf1.org/v1/laps?country_code=a%26b&team_name=Red+Bull+Racing",
+		},
+		{
+			name: "last value wins",
+			args: []Arg{{Key: "driver_number", Value: "1"}, {Key: "driver_number", Value: "44"}},
+			want: "https://api.openf1.org/v1/laps?driver_number=44",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := UrlBuilder("https://api.openf1.org/v1/laps", tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u.String(); got != tt.want {
+				t.Errorf("UrlBuilder() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := UrlBuilder("://missing-scheme", nil); err == nil {
+		t.Error("UrlBuilder() accepted an invalid URL")
+	}
+}
@@ -43,7 +43,7 @@ func (c *Client) GetWeather(weather Weather) (WeatherResponse, error) {
 	}
 
 	// Make an HTTP GET request to fetch the weather data
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +67,7 @@ func (c *Client) GetLatestWeather() (Weather, error) {
 	}
 
 	// Make an HTTP GET request to fetch the latest weather data
-	resp, err := GetHTTPRequest(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return Weather{}, err
 	}
@@ -85,3 +85,27 @@ func (c *Client) GetLatestWeather() (Weather, error) {
 	// Return the first (most recent) weather record
 	return weatherResponse[0], nil
 }
+
+// GetLatestSessionWeather fetches every weather record of the latest session
+func (c *Client) GetLatestSessionWeather() (WeatherResponse, error) {
+	var weatherResponse WeatherResponse
+
+	// Build the URL with query parameters for the latest session
+	url, err := UrlBuilder(c.getWeatherURL(), c.getLatestSessionArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	// Make an HTTP GET request to fetch the weather data
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response into the WeatherResponse struct
+	if err := json.Unmarshal(resp, &weatherResponse); err != nil {
+		return nil, err
+	}
+
+	return weatherResponse, nil
+}